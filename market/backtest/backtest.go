@@ -0,0 +1,148 @@
+// Package backtest 基于已有的market.Data计算管线做历史回放，
+// 让策略可以在不访问任何实时接口的情况下用历史K线验证效果。
+// 调用方先用market.HistoricalSource.FetchKlines或market.Client.Prefetch+磁盘缓存
+// 拿到覆盖目标日期范围(含预热窗口)的K线，再交给Runner逐根回放。
+package backtest
+
+import (
+	"fmt"
+
+	"github.com/PerryQiu/nofx/market"
+)
+
+// Signal 是策略在某根K线收盘时给出的交易信号
+type Signal int
+
+const (
+	// Hold 不开仓/不平仓，维持当前持仓状态
+	Hold Signal = iota
+	// Long 开多仓(若已持有多仓则继续持有；若持有空仓则先平仓再反手开多)
+	Long
+	// Short 开空仓(若已持有空仓则继续持有；若持有多仓则先平仓再反手开空)
+	Short
+	// ExitPosition 平掉当前持仓，转为空仓观望
+	ExitPosition
+)
+
+// OIFundingLookup 按K线收盘时间查询历史OI/资金费率；找不到对应时间点的历史数据时ok返回false，
+// 此时Runner会按market.OIData.Historical=true的零值处理，与实盘因数据源不支持而降级的行为一致
+type OIFundingLookup func(closeTime int64) (oi *market.OIData, fundingRate float64, ok bool)
+
+// defaultWarmupBars 与market.calculateIntradayLimit的下限保持一致，
+// 保证回放到第一根K线时EMA20/MACD(26)/RSI7等指标已经有足够的历史数据可用
+const defaultWarmupBars = 40
+
+// Runner 逐根回放历史K线，在每根K线收盘时合成一份与market.GetWithSource完全相同管线产出的
+// *market.Data快照，交给策略回调产生信号，并汇总成交易报告
+type Runner struct {
+	Symbol              string
+	ScanIntervalMinutes int
+	IntradayKlines      []market.Kline  // 主周期K线，按OpenTime升序，需包含足够的预热窗口
+	Klines4h            []market.Kline  // 4小时K线，用于长期趋势判断
+	OIFunding           OIFundingLookup // 可为nil，此时OI/资金费率按历史零值处理
+	Strategy            func(*market.Data) Signal
+
+	warmupBars int
+}
+
+// NewRunner 创建一个Runner，从第defaultWarmupBars根K线开始逐根回放
+func NewRunner(symbol string, scanIntervalMinutes int, intraday, klines4h []market.Kline, oiFunding OIFundingLookup, strategy func(*market.Data) Signal) *Runner {
+	return &Runner{
+		Symbol:              symbol,
+		ScanIntervalMinutes: scanIntervalMinutes,
+		IntradayKlines:      intraday,
+		Klines4h:            klines4h,
+		OIFunding:           oiFunding,
+		Strategy:            strategy,
+		warmupBars:          defaultWarmupBars,
+	}
+}
+
+// Run 从预热窗口之后逐根回放IntradayKlines，返回汇总报告
+func (r *Runner) Run() (*Report, error) {
+	if len(r.IntradayKlines) <= r.warmupBars {
+		return nil, fmt.Errorf("K线数量不足以完成预热(需要大于%d根，实际%d根)", r.warmupBars, len(r.IntradayKlines))
+	}
+
+	report := newReport()
+	var open *openPosition
+
+	for i := r.warmupBars; i < len(r.IntradayKlines); i++ {
+		bar := r.IntradayKlines[i]
+		source := &windowSource{
+			symbol:    r.Symbol,
+			intraday:  r.IntradayKlines[:i+1],
+			klines4h:  klines4hAsOf(r.Klines4h, bar.CloseTime),
+			oiFunding: r.OIFunding,
+		}
+
+		data, err := market.GetWithSource(source, r.Symbol, r.ScanIntervalMinutes)
+		if err != nil {
+			return nil, fmt.Errorf("合成第%d根K线快照失败: %v", i, err)
+		}
+
+		open = report.apply(r.Strategy(data), bar, open)
+	}
+
+	if open != nil {
+		report.close(open, r.IntradayKlines[len(r.IntradayKlines)-1])
+	}
+
+	report.finalize(r.IntradayKlines[r.warmupBars:])
+	return report, nil
+}
+
+// klines4hAsOf 返回截至closeTime为止的4小时K线，使长期趋势判断不会看到"未来"数据
+func klines4hAsOf(klines4h []market.Kline, closeTime int64) []market.Kline {
+	i := 0
+	for i < len(klines4h) && klines4h[i].CloseTime <= closeTime {
+		i++
+	}
+	return klines4h[:i]
+}
+
+// windowSource 是market.Source的一次性实现，只服务Runner在某一根K线时间点上发起的FetchKlines调用，
+// 让回放可以复用与实盘完全相同的指标计算管线(market.GetWithSource)
+type windowSource struct {
+	symbol    string
+	intraday  []market.Kline
+	klines4h  []market.Kline
+	oiFunding OIFundingLookup
+}
+
+func (s *windowSource) Normalize(symbol string) string {
+	return market.Normalize(symbol)
+}
+
+func (s *windowSource) FetchKlines(symbol, interval string, limit int) ([]market.Kline, error) {
+	klines := s.intraday
+	if interval == "4h" {
+		klines = s.klines4h
+	}
+	if limit > 0 && len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+	return klines, nil
+}
+
+func (s *windowSource) FetchOpenInterest(symbol string) (*market.OIData, error) {
+	if s.oiFunding != nil {
+		if oi, _, ok := s.oiFunding(s.now()); ok {
+			return oi, nil
+		}
+	}
+	return &market.OIData{Historical: true}, nil
+}
+
+func (s *windowSource) FetchFundingRate(symbol string) (float64, error) {
+	if s.oiFunding != nil {
+		if _, rate, ok := s.oiFunding(s.now()); ok {
+			return rate, nil
+		}
+	}
+	return 0, nil
+}
+
+func (s *windowSource) now() int64 {
+	return s.intraday[len(s.intraday)-1].CloseTime
+}
@@ -0,0 +1,151 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/PerryQiu/nofx/market"
+)
+
+// uptrendKlines 生成一段持续上涨、带小幅震荡的K线，OpenTime/CloseTime按1分钟递增
+func uptrendKlines(n int) []market.Kline {
+	klines := make([]market.Kline, n)
+	price := 100.0
+	var t int64 = 60_000
+
+	for i := 0; i < n; i++ {
+		open := price
+		price += 0.5
+		close := price
+		high := close + 0.2
+		low := open - 0.2
+
+		klines[i] = market.Kline{
+			OpenTime:  t,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    100,
+			CloseTime: t + 59_999,
+		}
+		t += 60_000
+	}
+	return klines
+}
+
+func TestRunnerAlwaysLongOnUptrendIsProfitable(t *testing.T) {
+	intraday := uptrendKlines(80)
+	klines4h := uptrendKlines(60)
+
+	var calls int
+	runner := NewRunner("BTCUSDT", 1, intraday, klines4h, nil, func(d *market.Data) Signal {
+		calls++
+		return Long
+	})
+
+	report, err := runner.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if calls != len(intraday)-defaultWarmupBars {
+		t.Fatalf("expected strategy to be called once per post-warmup bar, got %d calls", calls)
+	}
+	if len(report.Trades) != 1 {
+		t.Fatalf("expected a single trade held until the end, got %d trades", len(report.Trades))
+	}
+	if report.Trades[0].PnLPercent <= 0 {
+		t.Fatalf("expected a profitable long on a steady uptrend, got PnL=%v", report.Trades[0].PnLPercent)
+	}
+	if report.WinRate != 1 {
+		t.Fatalf("expected win rate 1, got %v", report.WinRate)
+	}
+}
+
+func TestRunnerHoldProducesNoTrades(t *testing.T) {
+	intraday := uptrendKlines(60)
+	klines4h := uptrendKlines(60)
+
+	runner := NewRunner("BTCUSDT", 1, intraday, klines4h, nil, func(d *market.Data) Signal {
+		return Hold
+	})
+
+	report, err := runner.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Trades) != 0 {
+		t.Fatalf("expected no trades when the strategy always holds, got %d", len(report.Trades))
+	}
+}
+
+func TestRunnerReversalClosesPriorTrade(t *testing.T) {
+	intraday := uptrendKlines(60)
+	klines4h := uptrendKlines(60)
+
+	i := 0
+	runner := NewRunner("BTCUSDT", 1, intraday, klines4h, nil, func(d *market.Data) Signal {
+		i++
+		if i == 1 {
+			return Long
+		}
+		return Short // 立刻反手，应先平掉多头仓位
+	})
+
+	report, err := runner.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Trades) != 2 {
+		t.Fatalf("expected the reversal to close the long and open a short, got %d trades", len(report.Trades))
+	}
+	if report.Trades[0].Side != Long {
+		t.Fatalf("expected first trade to be Long, got %v", report.Trades[0].Side)
+	}
+}
+
+func TestRunnerWithoutOIFundingMarksHistorical(t *testing.T) {
+	intraday := uptrendKlines(60)
+	klines4h := uptrendKlines(60)
+
+	var sawHistorical bool
+	runner := NewRunner("BTCUSDT", 1, intraday, klines4h, nil, func(d *market.Data) Signal {
+		if d.OpenInterest != nil && d.OpenInterest.Historical {
+			sawHistorical = true
+		}
+		return Hold
+	})
+
+	if _, err := runner.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !sawHistorical {
+		t.Fatalf("expected OpenInterest.Historical=true when no OIFundingLookup is provided")
+	}
+}
+
+func TestRunnerTooFewKlinesReturnsError(t *testing.T) {
+	intraday := uptrendKlines(10)
+	klines4h := uptrendKlines(10)
+
+	runner := NewRunner("BTCUSDT", 1, intraday, klines4h, nil, func(d *market.Data) Signal {
+		return Hold
+	})
+
+	if _, err := runner.Run(); err == nil {
+		t.Fatalf("expected an error when there are fewer klines than the warmup window")
+	}
+}
+
+func TestPremiumHitRates(t *testing.T) {
+	klines := uptrendKlines(30)
+
+	rates := premiumHitRates(klines, 5, PremiumThresholds)
+
+	if rates[0.01] == 0 {
+		t.Fatalf("expected a non-zero hit rate at the 1%% tier on a steady uptrend")
+	}
+	if rates[0.05] != 0 {
+		t.Fatalf("expected no hits at the 5%% tier over a gentle 0.5-per-bar uptrend, got %v", rates[0.05])
+	}
+}
@@ -0,0 +1,154 @@
+package backtest
+
+import (
+	"math"
+
+	"github.com/PerryQiu/nofx/market"
+)
+
+// PremiumThresholds 默认统计的涨跌幅档位，对应"GoodCase"命中率里常用的1%/2%/3%/5%分档
+var PremiumThresholds = []float64{0.01, 0.02, 0.03, 0.05}
+
+// PremiumLookforwardBars 统计premium-tier命中率时向后看的K线数量
+const PremiumLookforwardBars = 5
+
+// openPosition 记录当前未平仓的持仓
+type openPosition struct {
+	side     Signal
+	entryBar market.Kline
+}
+
+// Trade 记录一笔已平仓的交易
+type Trade struct {
+	Side       Signal
+	EntryTime  int64
+	ExitTime   int64
+	EntryPrice float64
+	ExitPrice  float64
+	PnLPercent float64
+}
+
+// Report 汇总一次回测的逐笔PnL、胜率、最大回撤和premium-tier命中率
+type Report struct {
+	Trades          []Trade
+	WinRate         float64
+	TotalPnLPercent float64
+	MaxDrawdown     float64             // 以累计PnL百分比计的最大回撤
+	PremiumHitRates map[float64]float64 // 阈值 -> 命中率，与具体策略信号无关
+}
+
+func newReport() *Report {
+	return &Report{}
+}
+
+// apply 根据策略信号更新持仓状态，返回更新后的(可能为nil的)未平仓持仓
+func (r *Report) apply(signal Signal, bar market.Kline, open *openPosition) *openPosition {
+	switch signal {
+	case Long:
+		if open == nil {
+			return &openPosition{side: Long, entryBar: bar}
+		}
+		if open.side == Short {
+			r.close(open, bar)
+			return &openPosition{side: Long, entryBar: bar}
+		}
+		return open
+	case Short:
+		if open == nil {
+			return &openPosition{side: Short, entryBar: bar}
+		}
+		if open.side == Long {
+			r.close(open, bar)
+			return &openPosition{side: Short, entryBar: bar}
+		}
+		return open
+	case ExitPosition:
+		if open != nil {
+			r.close(open, bar)
+		}
+		return nil
+	default: // Hold
+		return open
+	}
+}
+
+// close 平掉一笔持仓，按方向计算PnL百分比并记录
+func (r *Report) close(open *openPosition, exitBar market.Kline) {
+	entryPrice := open.entryBar.Close
+	exitPrice := exitBar.Close
+
+	pnl := (exitPrice - entryPrice) / entryPrice
+	if open.side == Short {
+		pnl = -pnl
+	}
+
+	r.Trades = append(r.Trades, Trade{
+		Side:       open.side,
+		EntryTime:  open.entryBar.CloseTime,
+		ExitTime:   exitBar.CloseTime,
+		EntryPrice: entryPrice,
+		ExitPrice:  exitPrice,
+		PnLPercent: pnl,
+	})
+}
+
+// finalize 基于已平仓交易计算胜率/总PnL/最大回撤，并基于回放窗口内的K线计算premium-tier命中率
+func (r *Report) finalize(klines []market.Kline) {
+	if len(r.Trades) > 0 {
+		wins := 0
+		equity, peak, maxDrawdown := 0.0, 0.0, 0.0
+
+		for _, t := range r.Trades {
+			if t.PnLPercent > 0 {
+				wins++
+			}
+			r.TotalPnLPercent += t.PnLPercent
+
+			equity += t.PnLPercent
+			if equity > peak {
+				peak = equity
+			}
+			if drawdown := peak - equity; drawdown > maxDrawdown {
+				maxDrawdown = drawdown
+			}
+		}
+
+		r.WinRate = float64(wins) / float64(len(r.Trades))
+		r.MaxDrawdown = maxDrawdown
+	}
+
+	r.PremiumHitRates = premiumHitRates(klines, PremiumLookforwardBars, PremiumThresholds)
+}
+
+// premiumHitRates 统计从任意K线收盘起lookforwardBars根K线之后价格变动超过各档位阈值的比例，
+// 反映该symbol/interval组合本身的波动强度，不依赖具体策略信号
+func premiumHitRates(klines []market.Kline, lookforwardBars int, thresholds []float64) map[float64]float64 {
+	hits := make(map[float64]int, len(thresholds))
+	total := 0
+
+	for i := 0; i+lookforwardBars < len(klines); i++ {
+		base := klines[i].Close
+		if base <= 0 {
+			continue
+		}
+		future := klines[i+lookforwardBars].Close
+		move := math.Abs((future - base) / base)
+
+		total++
+		for _, th := range thresholds {
+			if move >= th {
+				hits[th]++
+			}
+		}
+	}
+
+	rates := make(map[float64]float64, len(thresholds))
+	for _, th := range thresholds {
+		if total == 0 {
+			rates[th] = 0
+			continue
+		}
+		rates[th] = float64(hits[th]) / float64(total)
+	}
+	return rates
+}
@@ -0,0 +1,50 @@
+package market
+
+import (
+	"strings"
+)
+
+// BinanceSource 从Binance USDT本位合约接口获取行情数据，是Source的默认实现
+type BinanceSource struct {
+	client *Client
+}
+
+// NewBinanceSource 创建一个使用默认Client(默认限流预算、不启用磁盘缓存)的BinanceSource
+func NewBinanceSource() *BinanceSource {
+	return &BinanceSource{client: NewClient()}
+}
+
+// NewBinanceSourceWithClient 创建一个使用自定义Client的BinanceSource，
+// 便于调用方开启磁盘缓存或自定义限流预算(参见WithCacheDir/WithWeightBudget)
+func NewBinanceSourceWithClient(client *Client) *BinanceSource {
+	return &BinanceSource{client: client}
+}
+
+// Normalize 标准化symbol,确保是USDT交易对
+func (s *BinanceSource) Normalize(symbol string) string {
+	return Normalize(symbol)
+}
+
+// FetchKlines 从Binance获取K线数据
+func (s *BinanceSource) FetchKlines(symbol, interval string, limit int) ([]Kline, error) {
+	return s.client.FetchKlines(symbol, interval, limit)
+}
+
+// FetchOpenInterest 从Binance获取OI数据
+func (s *BinanceSource) FetchOpenInterest(symbol string) (*OIData, error) {
+	return s.client.FetchOpenInterest(symbol)
+}
+
+// FetchFundingRate 从Binance获取资金费率
+func (s *BinanceSource) FetchFundingRate(symbol string) (float64, error) {
+	return s.client.FetchFundingRate(symbol)
+}
+
+// Normalize 标准化symbol,确保是USDT交易对
+func Normalize(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	if strings.HasSuffix(symbol, "USDT") {
+		return symbol
+	}
+	return symbol + "USDT"
+}
@@ -0,0 +1,218 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// BybitSource 从Bybit v5 线性合约接口获取行情数据，是Source的第二种实现，
+// 使得本模块不再绑定Binance一家交易所
+type BybitSource struct{}
+
+// NewBybitSource 创建一个BybitSource
+func NewBybitSource() *BybitSource {
+	return &BybitSource{}
+}
+
+// Normalize 标准化symbol,确保是USDT交易对(Bybit线性合约与Binance命名一致)
+func (s *BybitSource) Normalize(symbol string) string {
+	return Normalize(symbol)
+}
+
+// FetchKlines 从Bybit获取K线数据
+func (s *BybitSource) FetchKlines(symbol, interval string, limit int) ([]Kline, error) {
+	bybitInterval, err := toBybitInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	intervalMs, err := intervalDurationMillis(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://api.bybit.com/v5/market/kline?category=linear&symbol=%s&interval=%s&limit=%d",
+		symbol, bybitInterval, limit)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List [][]string `json:"list"`
+		} `json:"result"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.RetCode != 0 {
+		return nil, fmt.Errorf("bybit kline error: %s", result.RetMsg)
+	}
+
+	// Bybit按最新在前返回，这里反转为与Binance一致的时间升序
+	rows := result.Result.List
+	klines := make([]Kline, len(rows))
+	for i, row := range rows {
+		// row: [start, open, high, low, close, volume, turnover]
+		openTime, _ := strconv.ParseInt(row[0], 10, 64)
+		open, _ := strconv.ParseFloat(row[1], 64)
+		high, _ := strconv.ParseFloat(row[2], 64)
+		low, _ := strconv.ParseFloat(row[3], 64)
+		close, _ := strconv.ParseFloat(row[4], 64)
+		volume, _ := strconv.ParseFloat(row[5], 64)
+
+		klines[len(rows)-1-i] = Kline{
+			OpenTime:  openTime,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    volume,
+			CloseTime: openTime + intervalMs - 1,
+		}
+	}
+
+	return klines, nil
+}
+
+// FetchOpenInterest 从Bybit获取OI数据
+func (s *BybitSource) FetchOpenInterest(symbol string) (*OIData, error) {
+	url := fmt.Sprintf("https://api.bybit.com/v5/market/open-interest?category=linear&symbol=%s&intervalTime=5min&limit=2",
+		symbol)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []struct {
+				OpenInterest string `json:"openInterest"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.RetCode != 0 {
+		return nil, fmt.Errorf("bybit open-interest error: %s", result.RetMsg)
+	}
+	if len(result.Result.List) == 0 {
+		return &OIData{}, nil
+	}
+
+	latest, _ := strconv.ParseFloat(result.Result.List[0].OpenInterest, 64)
+
+	sum := 0.0
+	for _, entry := range result.Result.List {
+		oi, _ := strconv.ParseFloat(entry.OpenInterest, 64)
+		sum += oi
+	}
+
+	return &OIData{
+		Latest:  latest,
+		Average: sum / float64(len(result.Result.List)),
+	}, nil
+}
+
+// FetchFundingRate 从Bybit获取最近一次结算的资金费率
+func (s *BybitSource) FetchFundingRate(symbol string) (float64, error) {
+	url := fmt.Sprintf("https://api.bybit.com/v5/market/funding/history?category=linear&symbol=%s&limit=1",
+		symbol)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []struct {
+				FundingRate string `json:"fundingRate"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	if result.RetCode != 0 {
+		return 0, fmt.Errorf("bybit funding-rate error: %s", result.RetMsg)
+	}
+	if len(result.Result.List) == 0 {
+		return 0, nil
+	}
+
+	rate, _ := strconv.ParseFloat(result.Result.List[0].FundingRate, 64)
+	return rate, nil
+}
+
+// bybitIntervalInfo 把Binance风格的interval("3m","4h"...)映射到Bybit使用的interval编号，
+// 以及该interval对应的毫秒时长(用于从只提供openTime的K线行推导CloseTime)
+type bybitIntervalInfo struct {
+	code       string
+	durationMs int64
+}
+
+var bybitIntervals = map[string]bybitIntervalInfo{
+	"1m":  {"1", 60_000},
+	"3m":  {"3", 3 * 60_000},
+	"5m":  {"5", 5 * 60_000},
+	"15m": {"15", 15 * 60_000},
+	"30m": {"30", 30 * 60_000},
+	"1h":  {"60", 60 * 60_000},
+	"4h":  {"240", 4 * 60 * 60_000},
+	"1d":  {"D", 24 * 60 * 60_000},
+}
+
+// toBybitInterval 把Binance风格的interval转换为Bybit使用的interval编号
+func toBybitInterval(interval string) (string, error) {
+	info, ok := bybitIntervals[interval]
+	if !ok {
+		return "", fmt.Errorf("unsupported interval for bybit: %s", interval)
+	}
+	return info.code, nil
+}
+
+// intervalDurationMillis 把Binance风格的interval换算成毫秒时长，
+// 用于从Bybit只提供的openTime推导出CloseTime(openTime+duration-1)，
+// 因为Bybit的kline行本身不带收盘时间字段
+func intervalDurationMillis(interval string) (int64, error) {
+	info, ok := bybitIntervals[interval]
+	if !ok {
+		return 0, fmt.Errorf("unsupported interval for bybit: %s", interval)
+	}
+	return info.durationMs, nil
+}
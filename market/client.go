@@ -0,0 +1,315 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Client 是带限流、重试和可选磁盘缓存的Binance HTTP客户端，
+// 取代原先获取K线/OI/资金费率时裸调用http.Get的写法，
+// 使扫描几十个symbol的短周期策略不会轻易触发Binance的IP封禁(429/418)
+type Client struct {
+	httpClient *http.Client
+	limiter    *tokenBucket
+	cache      *klineCache // 为nil时不启用磁盘缓存
+	maxRetries int
+}
+
+// ClientOption 用于在NewClient时定制Client的行为
+type ClientOption func(*Client)
+
+// WithCacheDir 开启基于dir目录的K线磁盘缓存，FetchKlines之后只会拉取自上次缓存截止时间以来的增量
+func WithCacheDir(dir string) ClientOption {
+	return func(c *Client) {
+		c.cache = newKlineCache(dir)
+	}
+}
+
+// WithWeightBudget 定制每分钟的权重预算(Binance USDT合约默认2400/分钟)
+func WithWeightBudget(weightPerMinute float64) ClientOption {
+	return func(c *Client) {
+		c.limiter = newTokenBucket(weightPerMinute, weightPerMinute/60)
+	}
+}
+
+// NewClient 创建一个使用默认限流/重试策略、默认不开启磁盘缓存的Client
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		limiter:    newTokenBucket(2400, 2400.0/60),
+		maxRetries: 5,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FetchKlines 获取K线；若启用了磁盘缓存，首次调用会拉取完整的limit根并写入缓存，
+// 之后的调用只补取自上次缓存截止时间以来的增量，合并后截取最近limit根返回
+func (c *Client) FetchKlines(symbol, interval string, limit int) ([]Kline, error) {
+	if c.cache == nil {
+		return c.fetchKlinesHTTP(symbol, interval, limit)
+	}
+
+	cached, err := c.cache.Load(symbol, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cached) == 0 {
+		fetched, err := c.fetchKlinesHTTP(symbol, interval, limit)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.cache.Append(symbol, interval, closedOnly(fetched)); err != nil {
+			return nil, err
+		}
+		return fetched, nil
+	}
+
+	last := cached[len(cached)-1]
+	fresh, err := c.fetchKlinesSince(symbol, interval, last.CloseTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.cache.Append(symbol, interval, closedOnly(fresh)); err != nil {
+		return nil, err
+	}
+
+	merged := mergeKlines(cached, fresh)
+	return tailKlines(merged, limit), nil
+}
+
+// Prefetch 为多个symbol按[from, to)区间批量拉取历史K线并写入磁盘缓存，
+// 让回测可以在不访问实时接口的情况下复用同一份缓存
+func (c *Client) Prefetch(symbols []string, interval string, from, to time.Time) error {
+	if c.cache == nil {
+		return fmt.Errorf("Prefetch需要先通过WithCacheDir启用磁盘缓存")
+	}
+
+	now := time.Now().UnixMilli()
+	for _, raw := range symbols {
+		symbol := Normalize(raw)
+		err := c.fetchKlinesPages(symbol, interval, from.UnixMilli(), to.UnixMilli(), func(page []Kline) error {
+			// to晚于当前时间时，最后一页末尾可能是尚未收盘的K线，不写入缓存
+			for len(page) > 0 && page[len(page)-1].CloseTime >= now {
+				page = page[:len(page)-1]
+			}
+			return c.cache.Append(symbol, interval, page)
+		})
+		if err != nil {
+			return fmt.Errorf("prefetch %s失败: %v", symbol, err)
+		}
+	}
+	return nil
+}
+
+// fetchKlinesPages 按[fromMs, toMs)分页拉取全部K线，每拉到一页就调用onPage；
+// toMs晚于当前时间时，最后一页末尾可能是尚未收盘的K线，不做过滤，
+// 由Prefetch和fetchKlinesSince共用，避免两处各写一份游标推进逻辑
+func (c *Client) fetchKlinesPages(symbol, interval string, fromMs, toMs int64, onPage func([]Kline) error) error {
+	cursor := fromMs
+
+	for cursor < toMs {
+		page, err := c.fetchKlinesRange(symbol, interval, cursor, toMs, 1000)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			break
+		}
+		if err := onPage(page); err != nil {
+			return err
+		}
+		cursor = page[len(page)-1].CloseTime + 1
+		if len(page) < 1000 {
+			break
+		}
+	}
+	return nil
+}
+
+// FetchOpenInterest 获取OI数据
+func (c *Client) FetchOpenInterest(symbol string) (*OIData, error) {
+	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/openInterest?symbol=%s", symbol)
+
+	body, err := c.doRequest(url, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		OpenInterest string `json:"openInterest"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	oi, _ := strconv.ParseFloat(result.OpenInterest, 64)
+	return &OIData{
+		Latest:  oi,
+		Average: oi * 0.999, // 近似平均值
+	}, nil
+}
+
+// FetchFundingRate 获取资金费率
+func (c *Client) FetchFundingRate(symbol string) (float64, error) {
+	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/premiumIndex?symbol=%s", symbol)
+
+	body, err := c.doRequest(url, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		LastFundingRate string `json:"lastFundingRate"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+
+	rate, _ := strconv.ParseFloat(result.LastFundingRate, 64)
+	return rate, nil
+}
+
+func (c *Client) fetchKlinesHTTP(symbol, interval string, limit int) ([]Kline, error) {
+	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/klines?symbol=%s&interval=%s&limit=%d",
+		symbol, interval, limit)
+	body, err := c.doRequest(url, klineWeight(limit))
+	if err != nil {
+		return nil, err
+	}
+	return parseKlinesResponse(body)
+}
+
+func (c *Client) fetchKlinesRange(symbol, interval string, startMs, endMs int64, limit int) ([]Kline, error) {
+	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/klines?symbol=%s&interval=%s&startTime=%d&endTime=%d&limit=%d",
+		symbol, interval, startMs, endMs, limit)
+	body, err := c.doRequest(url, klineWeight(limit))
+	if err != nil {
+		return nil, err
+	}
+	return parseKlinesResponse(body)
+}
+
+// fetchKlinesSince 分页拉取sinceCloseTime之后的全部K线(最后一根可能尚未收盘)，直到追上当前时间为止，
+// 而不是只取一页——否则本地缓存闲置超过一页(1000根)后恢复时，拼接结果仍停留在过去，
+// FetchKlines会悄无声息地返回一份过期的CurrentPrice/指标快照
+func (c *Client) fetchKlinesSince(symbol, interval string, sinceCloseTime int64) ([]Kline, error) {
+	var all []Kline
+	err := c.fetchKlinesPages(symbol, interval, sinceCloseTime+1, time.Now().UnixMilli(), func(page []Kline) error {
+		all = append(all, page...)
+		return nil
+	})
+	return all, err
+}
+
+// klineWeight 近似Binance对/fapi/v1/klines按limit分档的权重
+func klineWeight(limit int) float64 {
+	switch {
+	case limit <= 100:
+		return 1
+	case limit <= 500:
+		return 2
+	case limit <= 1000:
+		return 5
+	default:
+		return 10
+	}
+}
+
+func parseKlinesResponse(body []byte) ([]Kline, error) {
+	var rawData [][]interface{}
+	if err := json.Unmarshal(body, &rawData); err != nil {
+		return nil, err
+	}
+
+	klines := make([]Kline, len(rawData))
+	for i, item := range rawData {
+		openTime := int64(item[0].(float64))
+		open, _ := parseFloat(item[1])
+		high, _ := parseFloat(item[2])
+		low, _ := parseFloat(item[3])
+		close, _ := parseFloat(item[4])
+		volume, _ := parseFloat(item[5])
+		closeTime := int64(item[6].(float64))
+
+		klines[i] = Kline{
+			OpenTime:  openTime,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    volume,
+			CloseTime: closeTime,
+		}
+	}
+
+	return klines, nil
+}
+
+// doRequest 消耗weight权重后发起请求，对429/418/5xx做指数退避重试(优先读取Retry-After)
+func (c *Client) doRequest(url string, weight float64) ([]byte, error) {
+	c.limiter.take(weight)
+
+	var lastErr error
+	backoff := 500 * time.Millisecond
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		body, status, header, err := c.rawGet(url)
+		if err == nil && status == http.StatusOK {
+			return body, nil
+		}
+
+		if err != nil {
+			lastErr = err
+		} else if status == 429 || status == 418 || status >= 500 {
+			lastErr = fmt.Errorf("binance返回%d: %s", status, string(body))
+			if wait := retryAfter(header); wait > 0 {
+				backoff = wait
+			}
+		} else {
+			return nil, fmt.Errorf("binance返回%d: %s", status, string(body))
+		}
+
+		if attempt == c.maxRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return nil, fmt.Errorf("请求%s重试%d次后仍失败: %v", url, c.maxRetries, lastErr)
+}
+
+func (c *Client) rawGet(url string) (body []byte, status int, retryAfterHeader string, err error) {
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return body, resp.StatusCode, resp.Header.Get("Retry-After"), nil
+}
+
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(header)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,200 @@
+package market
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// CompositeSource 并行从多个交易所拉取行情并汇总为单一视图：
+// K线取各家收盘价的中位数(其余OHLCV沿用第一个成功返回的venue)，
+// OI取各家之和，资金费率取各家均值，同时保留FundingRateSpread供上层判断跨所套利空间
+type CompositeSource struct {
+	Sources []Source
+}
+
+// NewCompositeSource 创建一个聚合多个venue的数据源，至少需要一个子数据源
+func NewCompositeSource(sources ...Source) *CompositeSource {
+	return &CompositeSource{Sources: sources}
+}
+
+// Normalize 使用第一个子数据源的标准化规则(各venue对USDT永续的命名目前一致)
+func (c *CompositeSource) Normalize(symbol string) string {
+	if len(c.Sources) == 0 {
+		return Normalize(symbol)
+	}
+	return c.Sources[0].Normalize(symbol)
+}
+
+// FetchKlines 并行从所有子数据源获取K线，按收盘价中位数重建为单一序列
+func (c *CompositeSource) FetchKlines(symbol, interval string, limit int) ([]Kline, error) {
+	results := make([][]Kline, len(c.Sources))
+	errs := make([]error, len(c.Sources))
+
+	var wg sync.WaitGroup
+	for i, src := range c.Sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+			results[i], errs[i] = src.FetchKlines(symbol, interval, limit)
+		}(i, src)
+	}
+	wg.Wait()
+
+	var ok [][]Kline
+	for i, klines := range results {
+		if errs[i] == nil && len(klines) > 0 {
+			ok = append(ok, klines)
+		}
+	}
+	if len(ok) == 0 {
+		return nil, fmt.Errorf("composite: 所有数据源均获取K线失败")
+	}
+
+	// 按OpenTime对齐，而不是按下标对齐：不同venue返回的根数可能不同，
+	// 或者某个venue当前这一根已收盘而另一个还没有，按下标对齐会把venue-A的bar t
+	// 和venue-B的bar t-1做中位数，得到错误的CurrentPrice/OpenTime且不会报错
+	type bucket struct {
+		base   Kline
+		closes []float64
+	}
+	byOpenTime := make(map[int64]*bucket)
+	for _, klines := range ok {
+		for _, k := range klines {
+			b, exists := byOpenTime[k.OpenTime]
+			if !exists {
+				b = &bucket{base: k}
+				byOpenTime[k.OpenTime] = b
+			}
+			b.closes = append(b.closes, k.Close)
+		}
+	}
+
+	// 只保留所有venue在该OpenTime都有数据的时间点，避免用部分venue的数据冒充全量中位数
+	openTimes := make([]int64, 0, len(byOpenTime))
+	for ot, b := range byOpenTime {
+		if len(b.closes) == len(ok) {
+			openTimes = append(openTimes, ot)
+		}
+	}
+	if len(openTimes) == 0 {
+		return nil, fmt.Errorf("composite: 各数据源K线时间点没有交集")
+	}
+	sort.Slice(openTimes, func(i, j int) bool { return openTimes[i] < openTimes[j] })
+
+	reconciled := make([]Kline, len(openTimes))
+	for i, ot := range openTimes {
+		b := byOpenTime[ot]
+		reconciled[i] = Kline{
+			OpenTime:  b.base.OpenTime,
+			Open:      b.base.Open,
+			High:      b.base.High,
+			Low:       b.base.Low,
+			Close:     median(b.closes),
+			Volume:    b.base.Volume,
+			CloseTime: b.base.CloseTime,
+		}
+	}
+
+	if limit > 0 && len(reconciled) > limit {
+		reconciled = reconciled[len(reconciled)-limit:]
+	}
+
+	return reconciled, nil
+}
+
+// FetchOpenInterest 并行获取各venue的OI并求和
+func (c *CompositeSource) FetchOpenInterest(symbol string) (*OIData, error) {
+	results := make([]*OIData, len(c.Sources))
+	errs := make([]error, len(c.Sources))
+
+	var wg sync.WaitGroup
+	for i, src := range c.Sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+			results[i], errs[i] = src.FetchOpenInterest(symbol)
+		}(i, src)
+	}
+	wg.Wait()
+
+	var latestSum, avgSum float64
+	var count int
+	for i, oi := range results {
+		if errs[i] != nil || oi == nil {
+			continue
+		}
+		latestSum += oi.Latest
+		avgSum += oi.Average
+		count++
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("composite: 所有数据源均获取OI失败")
+	}
+
+	return &OIData{Latest: latestSum, Average: avgSum}, nil
+}
+
+// FetchFundingRate 并行获取各venue的资金费率并返回均值(跨所价差见FundingRateSpread)
+func (c *CompositeSource) FetchFundingRate(symbol string) (float64, error) {
+	rate, _, err := c.fetchFundingRates(symbol)
+	return rate, err
+}
+
+// FundingRateSpread 返回各venue资金费率中最高与最低之差，用于识别跨所资金费套利机会
+func (c *CompositeSource) FundingRateSpread(symbol string) (float64, error) {
+	_, spread, err := c.fetchFundingRates(symbol)
+	return spread, err
+}
+
+func (c *CompositeSource) fetchFundingRates(symbol string) (avg, spread float64, err error) {
+	rates := make([]float64, len(c.Sources))
+	errs := make([]error, len(c.Sources))
+
+	var wg sync.WaitGroup
+	for i, src := range c.Sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+			rates[i], errs[i] = src.FetchFundingRate(symbol)
+		}(i, src)
+	}
+	wg.Wait()
+
+	var ok []float64
+	for i, rate := range rates {
+		if errs[i] == nil {
+			ok = append(ok, rate)
+		}
+	}
+	if len(ok) == 0 {
+		return 0, 0, fmt.Errorf("composite: 所有数据源均获取资金费率失败")
+	}
+
+	sum := 0.0
+	min, max := ok[0], ok[0]
+	for _, r := range ok {
+		sum += r
+		if r < min {
+			min = r
+		}
+		if r > max {
+			max = r
+		}
+	}
+
+	return sum / float64(len(ok)), max - min, nil
+}
+
+// median 计算一组float64的中位数(会就地排序传入的切片)
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
@@ -1,13 +1,12 @@
 package market
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"math"
-	"net/http"
-	"strconv"
 	"strings"
+
+	"github.com/PerryQiu/nofx/market/indicator"
+	"github.com/PerryQiu/nofx/market/pattern"
 )
 
 // Data 市场数据结构
@@ -23,12 +22,15 @@ type Data struct {
 	FundingRate       float64
 	IntradaySeries    *IntradayData
 	LongerTermContext *LongerTermData
+	Shape             uint64          // 最近K线命中的蜡烛图形态位掩码，位定义见market/pattern包
+	Patterns          []pattern.Match // Shape对应的可读形态列表
 }
 
 // OIData Open Interest数据
 type OIData struct {
-	Latest  float64
-	Average float64
+	Latest     float64
+	Average    float64
+	Historical bool // true表示数据源无法提供该时间点的OI(如历史CSV数据源、回测查不到历史数据)，Latest/Average为零值，而非实时接口返回
 }
 
 // IntradayData 日内数据(短期间隔，由scan_interval_minutes决定)
@@ -38,19 +40,32 @@ type IntradayData struct {
 	MACDValues  []float64
 	RSI7Values  []float64
 	RSI14Values []float64
-	Interval    string // K线间隔，如 "3m", "5m", "15m"
+	Interval    string  // K线间隔，如 "3m", "5m", "15m"
+	VWAP        float64 // 成交量加权平均价，基于本次获取到的日内K线窗口
+	OBV         float64 // 能量潮，基于本次获取到的日内K线窗口
 }
 
 // LongerTermData 长期数据(4小时时间框架)
 type LongerTermData struct {
-	EMA20         float64
-	EMA50         float64
-	ATR3          float64
-	ATR14         float64
-	CurrentVolume float64
-	AverageVolume float64
-	MACDValues    []float64
-	RSI14Values   []float64
+	EMA20             float64
+	EMA50             float64
+	ATR3              float64
+	ATR14             float64
+	CurrentVolume     float64
+	AverageVolume     float64
+	MACDValues        []float64
+	RSI14Values       []float64
+	BollingerMid      float64 // 布林带中轨(20周期SMA)
+	BollingerUpper    float64 // 布林带上轨
+	BollingerLower    float64 // 布林带下轨
+	Supertrend        float64 // Supertrend当前生效的band值
+	SupertrendBullish bool    // Supertrend趋势方向，true为多头
+	DIPlus            float64 // +DI(14周期)
+	DIMinus           float64 // -DI(14周期)
+	ADX               float64 // 平均趋向指数(14周期)
+	StochK            float64 // 随机指标%K(14,3)
+	StochD            float64 // 随机指标%D(14,3)
+	CCI               float64 // 顺势指标(20周期)
 }
 
 // Kline K线数据
@@ -64,10 +79,16 @@ type Kline struct {
 	CloseTime int64
 }
 
-// GetWithInterval 获取指定代币的市场数据（根据扫描间隔动态调整）
+// GetWithInterval 获取指定代币的市场数据（根据扫描间隔动态调整），使用DefaultSource(Binance)
 func GetWithInterval(symbol string, scanIntervalMinutes int) (*Data, error) {
+	return GetWithSource(DefaultSource, symbol, scanIntervalMinutes)
+}
+
+// GetWithSource 获取指定代币的市场数据，数据来自source(可以是Binance/Bybit/Composite/Historical等任意实现)
+// 这是market模块对外的通用入口：回测、多交易所聚合都只需要传入不同的Source即可复用同一套指标计算管线
+func GetWithSource(source Source, symbol string, scanIntervalMinutes int) (*Data, error) {
 	// 标准化symbol
-	symbol = Normalize(symbol)
+	symbol = source.Normalize(symbol)
 
 	// 根据扫描间隔选择合适的K线间隔
 	interval := selectInterval(scanIntervalMinutes)
@@ -77,13 +98,13 @@ func GetWithInterval(symbol string, scanIntervalMinutes int) (*Data, error) {
 	intradayLimit := calculateIntradayLimit(scanIntervalMinutes)
 
 	// 获取短期K线数据
-	klinesIntraday, err := getKlines(symbol, interval, intradayLimit)
+	klinesIntraday, err := source.FetchKlines(symbol, interval, intradayLimit)
 	if err != nil {
 		return nil, fmt.Errorf("获取%s K线失败: %v", interval, err)
 	}
 
 	// 获取4小时K线数据 (用于长期趋势判断)
-	klines4h, err := getKlines(symbol, "4h", 60) // 多获取用于计算指标
+	klines4h, err := source.FetchKlines(symbol, "4h", 60) // 多获取用于计算指标
 	if err != nil {
 		return nil, fmt.Errorf("获取4小时K线失败: %v", err)
 	}
@@ -116,14 +137,14 @@ func GetWithInterval(symbol string, scanIntervalMinutes int) (*Data, error) {
 	}
 
 	// 获取OI数据
-	oiData, err := getOpenInterestData(symbol)
+	oiData, err := source.FetchOpenInterest(symbol)
 	if err != nil {
 		// OI失败不影响整体,使用默认值
 		oiData = &OIData{Latest: 0, Average: 0}
 	}
 
 	// 获取Funding Rate
-	fundingRate, _ := getFundingRate(symbol)
+	fundingRate, _ := source.FetchFundingRate(symbol)
 
 	// 计算日内系列数据
 	intradayData := calculateIntradaySeries(klinesIntraday)
@@ -132,6 +153,11 @@ func GetWithInterval(symbol string, scanIntervalMinutes int) (*Data, error) {
 	// 计算长期数据
 	longerTermData := calculateLongerTermData(klines4h)
 
+	// 用短期K线的最近几根识别蜡烛图形态，阈值基于同一时间框架的ATR14，
+	// 不能用4小时ATR14——两者尺度相差一个时间框架比例，会导致形态判定全面失准
+	intradayATR14 := calculateATR(klinesIntraday, 14)
+	shape, matches := pattern.Classify(toBars(klinesIntraday), intradayATR14)
+
 	return &Data{
 		Symbol:            symbol,
 		CurrentPrice:      currentPrice,
@@ -144,6 +170,8 @@ func GetWithInterval(symbol string, scanIntervalMinutes int) (*Data, error) {
 		FundingRate:       fundingRate,
 		IntradaySeries:    intradayData,
 		LongerTermContext: longerTermData,
+		Shape:             shape,
+		Patterns:          matches,
 	}, nil
 }
 
@@ -182,51 +210,6 @@ func calculateIntradayLimit(scanIntervalMinutes int) int {
 	return barsFor2Hours
 }
 
-// getKlines 从Binance获取K线数据
-func getKlines(symbol, interval string, limit int) ([]Kline, error) {
-	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/klines?symbol=%s&interval=%s&limit=%d",
-		symbol, interval, limit)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var rawData [][]interface{}
-	if err := json.Unmarshal(body, &rawData); err != nil {
-		return nil, err
-	}
-
-	klines := make([]Kline, len(rawData))
-	for i, item := range rawData {
-		openTime := int64(item[0].(float64))
-		open, _ := parseFloat(item[1])
-		high, _ := parseFloat(item[2])
-		low, _ := parseFloat(item[3])
-		close, _ := parseFloat(item[4])
-		volume, _ := parseFloat(item[5])
-		closeTime := int64(item[6].(float64))
-
-		klines[i] = Kline{
-			OpenTime:  openTime,
-			Open:      open,
-			High:      high,
-			Low:       low,
-			Close:     close,
-			Volume:    volume,
-			CloseTime: closeTime,
-		}
-	}
-
-	return klines, nil
-}
-
 // calculateEMA 计算EMA
 func calculateEMA(klines []Kline, period int) float64 {
 	if len(klines) < period {
@@ -383,9 +366,28 @@ func calculateIntradaySeries(klines []Kline) *IntradayData {
 		}
 	}
 
+	bars := toBars(klines)
+	data.VWAP = indicator.VWAP(bars)
+	data.OBV = indicator.OBV(bars)
+
 	return data
 }
 
+// toBars 把Kline切片转换为indicator包使用的Bar切片
+func toBars(klines []Kline) []indicator.Bar {
+	bars := make([]indicator.Bar, len(klines))
+	for i, k := range klines {
+		bars[i] = indicator.Bar{
+			Open:   k.Open,
+			High:   k.High,
+			Low:    k.Low,
+			Close:  k.Close,
+			Volume: k.Volume,
+		}
+	}
+	return bars
+}
+
 // calculateLongerTermData 计算长期数据
 func calculateLongerTermData(klines []Kline) *LongerTermData {
 	data := &LongerTermData{
@@ -429,73 +431,15 @@ func calculateLongerTermData(klines []Kline) *LongerTermData {
 		}
 	}
 
-	return data
-}
-
-// getOpenInterestData 获取OI数据
-func getOpenInterestData(symbol string) (*OIData, error) {
-	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/openInterest?symbol=%s", symbol)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var result struct {
-		OpenInterest string `json:"openInterest"`
-		Symbol       string `json:"symbol"`
-		Time         int64  `json:"time"`
-	}
-
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
-	}
-
-	oi, _ := strconv.ParseFloat(result.OpenInterest, 64)
+	// 计算扩展指标套件(布林带/Supertrend/DMI-ADX/随机指标/CCI)
+	bars := toBars(klines)
+	data.BollingerMid, data.BollingerUpper, data.BollingerLower = indicator.Bollinger(bars, 20, 2)
+	data.Supertrend, data.SupertrendBullish = indicator.Supertrend(bars, 10, 3)
+	data.DIPlus, data.DIMinus, data.ADX = indicator.DMIADX(bars, 14)
+	data.StochK, data.StochD = indicator.StochasticKD(bars, 14, 3)
+	data.CCI = indicator.CCI(bars, 20)
 
-	return &OIData{
-		Latest:  oi,
-		Average: oi * 0.999, // 近似平均值
-	}, nil
-}
-
-// getFundingRate 获取资金费率
-func getFundingRate(symbol string) (float64, error) {
-	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/premiumIndex?symbol=%s", symbol)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-
-	var result struct {
-		Symbol          string `json:"symbol"`
-		MarkPrice       string `json:"markPrice"`
-		IndexPrice      string `json:"indexPrice"`
-		LastFundingRate string `json:"lastFundingRate"`
-		NextFundingTime int64  `json:"nextFundingTime"`
-		InterestRate    string `json:"interestRate"`
-		Time            int64  `json:"time"`
-	}
-
-	if err := json.Unmarshal(body, &result); err != nil {
-		return 0, err
-	}
-
-	rate, _ := strconv.ParseFloat(result.LastFundingRate, 64)
-	return rate, nil
+	return data
 }
 
 // Format 格式化输出市场数据
@@ -511,6 +455,9 @@ func Format(data *Data) string {
 	if data.OpenInterest != nil {
 		sb.WriteString(fmt.Sprintf("Open Interest: Latest: %.2f Average: %.2f\n\n",
 			data.OpenInterest.Latest, data.OpenInterest.Average))
+		if data.OpenInterest.Historical {
+			sb.WriteString("(no OI data available for this period, values are zeroed)\n\n")
+		}
 	}
 
 	sb.WriteString(fmt.Sprintf("Funding Rate: %.2e\n\n", data.FundingRate))
@@ -541,6 +488,8 @@ func Format(data *Data) string {
 		if len(data.IntradaySeries.RSI14Values) > 0 {
 			sb.WriteString(fmt.Sprintf("RSI indicators (14‑Period): %s\n\n", formatFloatSlice(data.IntradaySeries.RSI14Values)))
 		}
+
+		sb.WriteString(fmt.Sprintf("VWAP: %.3f, OBV: %.3f\n\n", data.IntradaySeries.VWAP, data.IntradaySeries.OBV))
 	}
 
 	if data.LongerTermContext != nil {
@@ -562,6 +511,35 @@ func Format(data *Data) string {
 		if len(data.LongerTermContext.RSI14Values) > 0 {
 			sb.WriteString(fmt.Sprintf("RSI indicators (14‑Period): %s\n\n", formatFloatSlice(data.LongerTermContext.RSI14Values)))
 		}
+
+		sb.WriteString(fmt.Sprintf("Bollinger Bands (20‑period, 2σ): mid %.3f, upper %.3f, lower %.3f\n\n",
+			data.LongerTermContext.BollingerMid, data.LongerTermContext.BollingerUpper, data.LongerTermContext.BollingerLower))
+
+		trendLabel := "bearish"
+		if data.LongerTermContext.SupertrendBullish {
+			trendLabel = "bullish"
+		}
+		sb.WriteString(fmt.Sprintf("Supertrend: %.3f (%s)\n\n", data.LongerTermContext.Supertrend, trendLabel))
+
+		sb.WriteString(fmt.Sprintf("DMI/ADX (14‑period): +DI %.3f, -DI %.3f, ADX %.3f\n\n",
+			data.LongerTermContext.DIPlus, data.LongerTermContext.DIMinus, data.LongerTermContext.ADX))
+
+		sb.WriteString(fmt.Sprintf("Stochastic (14,3): %%K %.3f, %%D %.3f\n\n",
+			data.LongerTermContext.StochK, data.LongerTermContext.StochD))
+
+		sb.WriteString(fmt.Sprintf("CCI (20‑period): %.3f\n\n", data.LongerTermContext.CCI))
+	}
+
+	if len(data.Patterns) > 0 {
+		sb.WriteString("Candlestick patterns detected in the most recent bars:\n\n")
+		for _, m := range data.Patterns {
+			direction := "bearish"
+			if m.Bullish {
+				direction = "bullish"
+			}
+			sb.WriteString(fmt.Sprintf("- %s (%s, strength %.2f)\n", m.Name, direction, m.Strength))
+		}
+		sb.WriteString("\n")
 	}
 
 	return sb.String()
@@ -575,28 +553,3 @@ func formatFloatSlice(values []float64) string {
 	}
 	return "[" + strings.Join(strValues, ", ") + "]"
 }
-
-// Normalize 标准化symbol,确保是USDT交易对
-func Normalize(symbol string) string {
-	symbol = strings.ToUpper(symbol)
-	if strings.HasSuffix(symbol, "USDT") {
-		return symbol
-	}
-	return symbol + "USDT"
-}
-
-// parseFloat 解析float值
-func parseFloat(v interface{}) (float64, error) {
-	switch val := v.(type) {
-	case string:
-		return strconv.ParseFloat(val, 64)
-	case float64:
-		return val, nil
-	case int:
-		return float64(val), nil
-	case int64:
-		return float64(val), nil
-	default:
-		return 0, fmt.Errorf("unsupported type: %T", v)
-	}
-}
@@ -0,0 +1,82 @@
+package market
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// HistoricalSource 从本地CSV文件读取K线，让回测复用与实盘完全相同的Data计算管线，
+// 不需要访问Binance/Bybit等HTTP接口。目录下文件按 "<symbol>_<interval>.csv" 命名，
+// 每行格式为: openTime,open,high,low,close,volume,closeTime
+type HistoricalSource struct {
+	Dir string
+}
+
+// NewHistoricalSource 创建一个从dir目录读取CSV K线的数据源
+func NewHistoricalSource(dir string) *HistoricalSource {
+	return &HistoricalSource{Dir: dir}
+}
+
+// Normalize 标准化symbol,确保是USDT交易对(与CSV文件命名保持一致)
+func (s *HistoricalSource) Normalize(symbol string) string {
+	return Normalize(symbol)
+}
+
+// FetchKlines 读取 "<symbol>_<interval>.csv"，返回最近limit根K线
+func (s *HistoricalSource) FetchKlines(symbol, interval string, limit int) ([]Kline, error) {
+	path := filepath.Join(s.Dir, fmt.Sprintf("%s_%s.csv", symbol, interval))
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	klines := make([]Kline, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 7 {
+			continue
+		}
+		openTime, _ := strconv.ParseInt(row[0], 10, 64)
+		open, _ := strconv.ParseFloat(row[1], 64)
+		high, _ := strconv.ParseFloat(row[2], 64)
+		low, _ := strconv.ParseFloat(row[3], 64)
+		close, _ := strconv.ParseFloat(row[4], 64)
+		volume, _ := strconv.ParseFloat(row[5], 64)
+		closeTime, _ := strconv.ParseInt(row[6], 10, 64)
+
+		klines = append(klines, Kline{
+			OpenTime:  openTime,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    volume,
+			CloseTime: closeTime,
+		})
+	}
+
+	if limit > 0 && len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+
+	return klines, nil
+}
+
+// FetchOpenInterest 历史CSV数据源不提供OI数据，返回零值并标记Historical
+func (s *HistoricalSource) FetchOpenInterest(symbol string) (*OIData, error) {
+	return &OIData{Historical: true}, nil
+}
+
+// FetchFundingRate 历史CSV数据源不提供资金费率，返回零值
+func (s *HistoricalSource) FetchFundingRate(symbol string) (float64, error) {
+	return 0, nil
+}
@@ -0,0 +1,38 @@
+package indicator
+
+import "math"
+
+// atrSeries 返回按Wilder平滑逐根对齐的ATR序列(下标0..period-1为0，从period起才有值)，
+// 供Supertrend这类需要“回放”整段历史的指标复用
+func atrSeries(bars []Bar, period int) []float64 {
+	atr := make([]float64, len(bars))
+	if len(bars) <= period {
+		return atr
+	}
+
+	trs := make([]float64, len(bars))
+	for i := 1; i < len(bars); i++ {
+		high := bars[i].High
+		low := bars[i].Low
+		prevClose := bars[i-1].Close
+
+		tr1 := high - low
+		tr2 := math.Abs(high - prevClose)
+		tr3 := math.Abs(low - prevClose)
+		trs[i] = math.Max(tr1, math.Max(tr2, tr3))
+	}
+
+	sum := 0.0
+	for i := 1; i <= period; i++ {
+		sum += trs[i]
+	}
+	value := sum / float64(period)
+	atr[period] = value
+
+	for i := period + 1; i < len(bars); i++ {
+		value = (value*float64(period-1) + trs[i]) / float64(period)
+		atr[i] = value
+	}
+
+	return atr
+}
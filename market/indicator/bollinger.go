@@ -0,0 +1,24 @@
+package indicator
+
+import "math"
+
+// Bollinger 计算布林带：中轨为period周期SMA，上下轨为中轨±k倍标准差
+func Bollinger(bars []Bar, period int, k float64) (mid, upper, lower float64) {
+	if len(bars) < period {
+		return 0, 0, 0
+	}
+
+	mid = sma(bars, period)
+
+	window := bars[len(bars)-period:]
+	variance := 0.0
+	for _, b := range window {
+		diff := b.Close - mid
+		variance += diff * diff
+	}
+	stddev := math.Sqrt(variance / float64(period))
+
+	upper = mid + k*stddev
+	lower = mid - k*stddev
+	return mid, upper, lower
+}
@@ -0,0 +1,33 @@
+package indicator
+
+import "math"
+
+// CCI 计算顺势指标：(TP - SMA(TP)) / (0.015 * 平均绝对偏差)，TP为典型价(H+L+C)/3
+func CCI(bars []Bar, period int) float64 {
+	if len(bars) < period {
+		return 0
+	}
+
+	window := bars[len(bars)-period:]
+	typicalPrices := make([]float64, period)
+	sum := 0.0
+	for i, b := range window {
+		tp := (b.High + b.Low + b.Close) / 3
+		typicalPrices[i] = tp
+		sum += tp
+	}
+	smaTP := sum / float64(period)
+
+	meanDeviation := 0.0
+	for _, tp := range typicalPrices {
+		meanDeviation += math.Abs(tp - smaTP)
+	}
+	meanDeviation /= float64(period)
+
+	if meanDeviation == 0 {
+		return 0
+	}
+
+	currentTP := typicalPrices[len(typicalPrices)-1]
+	return (currentTP - smaTP) / (0.015 * meanDeviation)
+}
@@ -0,0 +1,90 @@
+package indicator
+
+import "math"
+
+// DMIADX 计算方向动量指标：+DM/-DM与TR先做Wilder平滑得到DI+/DI-，
+// DX = 100*|DI+ - DI-|/(DI+ + DI-)，ADX再对DX做一次Wilder平滑
+func DMIADX(bars []Bar, period int) (diPlus, diMinus, adx float64) {
+	n := len(bars)
+	if n <= period*2 {
+		return 0, 0, 0
+	}
+
+	plusDM := make([]float64, n)
+	minusDM := make([]float64, n)
+	trs := make([]float64, n)
+
+	for i := 1; i < n; i++ {
+		upMove := bars[i].High - bars[i-1].High
+		downMove := bars[i-1].Low - bars[i].Low
+
+		if upMove > downMove && upMove > 0 {
+			plusDM[i] = upMove
+		}
+		if downMove > upMove && downMove > 0 {
+			minusDM[i] = downMove
+		}
+
+		high := bars[i].High
+		low := bars[i].Low
+		prevClose := bars[i-1].Close
+		tr1 := high - low
+		tr2 := math.Abs(high - prevClose)
+		tr3 := math.Abs(low - prevClose)
+		trs[i] = math.Max(tr1, math.Max(tr2, tr3))
+	}
+
+	smoothedTR := sumRange(trs, 1, period)
+	smoothedPlusDM := sumRange(plusDM, 1, period)
+	smoothedMinusDM := sumRange(minusDM, 1, period)
+
+	dxValues := make([]float64, 0, n-period)
+
+	for i := period + 1; i < n; i++ {
+		smoothedTR = smoothedTR - smoothedTR/float64(period) + trs[i]
+		smoothedPlusDM = smoothedPlusDM - smoothedPlusDM/float64(period) + plusDM[i]
+		smoothedMinusDM = smoothedMinusDM - smoothedMinusDM/float64(period) + minusDM[i]
+
+		if smoothedTR == 0 {
+			continue
+		}
+
+		diPlus = 100 * smoothedPlusDM / smoothedTR
+		diMinus = 100 * smoothedMinusDM / smoothedTR
+
+		denom := diPlus + diMinus
+		if denom == 0 {
+			continue
+		}
+		dxValues = append(dxValues, 100*math.Abs(diPlus-diMinus)/denom)
+	}
+
+	if len(dxValues) == 0 {
+		return diPlus, diMinus, 0
+	}
+
+	adxCount := period
+	if len(dxValues) < adxCount {
+		adxCount = len(dxValues)
+	}
+
+	sum := 0.0
+	for _, dx := range dxValues[:adxCount] {
+		sum += dx
+	}
+	adx = sum / float64(adxCount)
+
+	for _, dx := range dxValues[adxCount:] {
+		adx = (adx*float64(period-1) + dx) / float64(period)
+	}
+
+	return diPlus, diMinus, adx
+}
+
+func sumRange(values []float64, start, count int) float64 {
+	sum := 0.0
+	for i := start; i < start+count && i < len(values); i++ {
+		sum += values[i]
+	}
+	return sum
+}
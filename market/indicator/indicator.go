@@ -0,0 +1,24 @@
+// Package indicator 提供market包之外的通用技术指标计算，
+// 工作在独立的Bar类型之上，避免反向依赖market包
+package indicator
+
+// Bar 是技术指标计算所需的一根K线(OHLCV)，字段含义与market.Kline一致
+type Bar struct {
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// sma 计算最近period根bar收盘价的简单移动平均
+func sma(bars []Bar, period int) float64 {
+	if len(bars) < period {
+		return 0
+	}
+	sum := 0.0
+	for _, b := range bars[len(bars)-period:] {
+		sum += b.Close
+	}
+	return sum / float64(period)
+}
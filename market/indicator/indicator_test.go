@@ -0,0 +1,118 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+)
+
+// uptrendBars 构造一段平稳上涨的K线，收盘价从100起每根上涨1，成交量固定为10
+func uptrendBars(n int) []Bar {
+	bars := make([]Bar, n)
+	for i := 0; i < n; i++ {
+		close := 100 + float64(i)
+		bars[i] = Bar{
+			Open:   close - 0.5,
+			High:   close + 1,
+			Low:    close - 1,
+			Close:  close,
+			Volume: 10,
+		}
+	}
+	return bars
+}
+
+func approxEqual(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestBollinger(t *testing.T) {
+	bars := uptrendBars(25)
+	mid, upper, lower := Bollinger(bars, 20, 2)
+
+	wantMid := sma(bars, 20)
+	if !approxEqual(mid, wantMid, 1e-9) {
+		t.Fatalf("mid = %v, want %v", mid, wantMid)
+	}
+	if upper <= mid || lower >= mid {
+		t.Fatalf("expected upper > mid > lower, got upper=%v mid=%v lower=%v", upper, mid, lower)
+	}
+}
+
+func TestBollingerInsufficientData(t *testing.T) {
+	mid, upper, lower := Bollinger(uptrendBars(5), 20, 2)
+	if mid != 0 || upper != 0 || lower != 0 {
+		t.Fatalf("expected zero values with insufficient data, got mid=%v upper=%v lower=%v", mid, upper, lower)
+	}
+}
+
+func TestSupertrendBullishInUptrend(t *testing.T) {
+	value, bullish := Supertrend(uptrendBars(30), 10, 3)
+	if !bullish {
+		t.Fatalf("expected bullish supertrend in a steady uptrend, got value=%v bullish=%v", value, bullish)
+	}
+	if value <= 0 {
+		t.Fatalf("expected a positive supertrend band value, got %v", value)
+	}
+}
+
+func TestDMIADXUptrend(t *testing.T) {
+	diPlus, diMinus, adx := DMIADX(uptrendBars(40), 14)
+	if diPlus <= diMinus {
+		t.Fatalf("expected DI+ > DI- in a steady uptrend, got DI+=%v DI-=%v", diPlus, diMinus)
+	}
+	if adx <= 0 {
+		t.Fatalf("expected a positive ADX, got %v", adx)
+	}
+}
+
+func TestVWAPBetweenLowAndHigh(t *testing.T) {
+	bars := uptrendBars(10)
+	vwap := VWAP(bars)
+
+	low, high := bars[0].Low, bars[len(bars)-1].High
+	if vwap < low || vwap > high {
+		t.Fatalf("expected vwap within [%v, %v], got %v", low, high, vwap)
+	}
+}
+
+func TestVWAPEmpty(t *testing.T) {
+	if vwap := VWAP(nil); vwap != 0 {
+		t.Fatalf("expected 0 for empty bars, got %v", vwap)
+	}
+}
+
+func TestOBVAccumulatesOnUptrend(t *testing.T) {
+	bars := uptrendBars(5)
+	obv := OBV(bars)
+
+	want := 0.0
+	for i := 1; i < len(bars); i++ {
+		want += bars[i].Volume
+	}
+	if obv != want {
+		t.Fatalf("obv = %v, want %v", obv, want)
+	}
+}
+
+func TestStochasticKDNearOverboughtInUptrend(t *testing.T) {
+	k, d := StochasticKD(uptrendBars(20), 14, 3)
+	if k < 90 {
+		t.Fatalf("expected %%K near 100 at the top of a steady uptrend, got %v", k)
+	}
+	if d <= 0 || d > 100 {
+		t.Fatalf("expected %%D within (0, 100], got %v", d)
+	}
+}
+
+func TestCCIPositiveInUptrend(t *testing.T) {
+	cci := CCI(uptrendBars(25), 20)
+	if cci <= 0 {
+		t.Fatalf("expected positive CCI while price trends above its moving average, got %v", cci)
+	}
+}
+
+func TestCCIInsufficientData(t *testing.T) {
+	if cci := CCI(uptrendBars(5), 20); cci != 0 {
+		t.Fatalf("expected 0 with insufficient data, got %v", cci)
+	}
+}
@@ -0,0 +1,19 @@
+package indicator
+
+// OBV 计算能量潮指标：从第二根K线起，按收盘价涨跌方向累加/累减成交量
+func OBV(bars []Bar) float64 {
+	if len(bars) == 0 {
+		return 0
+	}
+
+	obv := 0.0
+	for i := 1; i < len(bars); i++ {
+		switch {
+		case bars[i].Close > bars[i-1].Close:
+			obv += bars[i].Volume
+		case bars[i].Close < bars[i-1].Close:
+			obv -= bars[i].Volume
+		}
+	}
+	return obv
+}
@@ -0,0 +1,49 @@
+package indicator
+
+// StochasticKD 计算随机指标：%K = 100*(close-lowN)/(highN-lowN)，
+// %D为最近dPeriod个%K值的简单移动平均
+func StochasticKD(bars []Bar, kPeriod, dPeriod int) (k, d float64) {
+	n := len(bars)
+	if n < kPeriod {
+		return 0, 0
+	}
+
+	count := dPeriod
+	if n-kPeriod+1 < count {
+		count = n - kPeriod + 1
+	}
+
+	kValues := make([]float64, 0, count)
+	for offset := count - 1; offset >= 0; offset-- {
+		end := n - offset
+		window := bars[end-kPeriod : end]
+
+		highest, lowest := window[0].High, window[0].Low
+		for _, b := range window {
+			if b.High > highest {
+				highest = b.High
+			}
+			if b.Low < lowest {
+				lowest = b.Low
+			}
+		}
+
+		if highest == lowest {
+			kValues = append(kValues, 50)
+			continue
+		}
+
+		closeAt := bars[end-1].Close
+		kValues = append(kValues, 100*(closeAt-lowest)/(highest-lowest))
+	}
+
+	k = kValues[len(kValues)-1]
+
+	sum := 0.0
+	for _, v := range kValues {
+		sum += v
+	}
+	d = sum / float64(len(kValues))
+
+	return k, d
+}
@@ -0,0 +1,53 @@
+package indicator
+
+// Supertrend 计算基础版Supertrend：basicUpper/basicLower = (H+L)/2 ± multiplier*ATR，
+// 趋势延续时沿用前一根的band(carryover)，只有价格突破band才翻转趋势方向。
+// 返回当前生效的band值，以及趋势是否为多头(bullish)
+func Supertrend(bars []Bar, atrPeriod int, multiplier float64) (value float64, bullish bool) {
+	if len(bars) <= atrPeriod {
+		return 0, false
+	}
+
+	atrs := atrSeries(bars, atrPeriod)
+
+	var finalUpper, finalLower float64
+	trendBullish := true
+	initialized := false
+
+	for i := atrPeriod; i < len(bars); i++ {
+		atr := atrs[i]
+		b := bars[i]
+		mid := (b.High + b.Low) / 2
+		basicUpper := mid + multiplier*atr
+		basicLower := mid - multiplier*atr
+
+		if !initialized {
+			finalUpper = basicUpper
+			finalLower = basicLower
+			trendBullish = b.Close >= finalLower
+			initialized = true
+			continue
+		}
+
+		prevClose := bars[i-1].Close
+		if basicUpper < finalUpper || prevClose > finalUpper {
+			finalUpper = basicUpper
+		}
+		if basicLower > finalLower || prevClose < finalLower {
+			finalLower = basicLower
+		}
+
+		if trendBullish {
+			if b.Close < finalLower {
+				trendBullish = false
+			}
+		} else if b.Close > finalUpper {
+			trendBullish = true
+		}
+	}
+
+	if trendBullish {
+		return finalLower, true
+	}
+	return finalUpper, false
+}
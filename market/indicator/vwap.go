@@ -0,0 +1,20 @@
+package indicator
+
+// VWAP 计算成交量加权平均价：Σ(典型价*成交量)/Σ成交量，典型价=(H+L+C)/3。
+// 传入的bars即为一个"session"的K线，调用方通过截取不同区间来实现按需重置
+func VWAP(bars []Bar) float64 {
+	if len(bars) == 0 {
+		return 0
+	}
+
+	var pvSum, volSum float64
+	for _, b := range bars {
+		typicalPrice := (b.High + b.Low + b.Close) / 3
+		pvSum += typicalPrice * b.Volume
+		volSum += b.Volume
+	}
+	if volSum == 0 {
+		return 0
+	}
+	return pvSum / volSum
+}
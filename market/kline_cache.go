@@ -0,0 +1,159 @@
+package market
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// klineCache 把已收盘的K线以CSV文件形式持久化到磁盘，按symbol+interval分文件存放，
+// 使Client.FetchKlines重复调用同一symbol+interval时只需要补取增量
+type klineCache struct {
+	dir string
+}
+
+func newKlineCache(dir string) *klineCache {
+	return &klineCache{dir: dir}
+}
+
+func (c *klineCache) path(symbol, interval string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s_%s.csv", symbol, interval))
+}
+
+// Load 读取磁盘上已缓存的K线，按OpenTime升序返回；文件不存在时返回空切片
+func (c *klineCache) Load(symbol, interval string) ([]Kline, error) {
+	f, err := os.Open(c.path(symbol, interval))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	klines := make([]Kline, 0, len(rows))
+	for _, row := range rows {
+		k, err := klineFromCSVRow(row)
+		if err != nil {
+			continue
+		}
+		klines = append(klines, k)
+	}
+	return klines, nil
+}
+
+// Append 把新的已收盘K线合并进缓存文件，按OpenTime去重并保持升序
+func (c *klineCache) Append(symbol, interval string, klines []Kline) error {
+	if len(klines) == 0 {
+		return nil
+	}
+
+	existing, err := c.Load(symbol, interval)
+	if err != nil {
+		return err
+	}
+	merged := mergeKlines(existing, klines)
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(c.path(symbol, interval))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	for _, k := range merged {
+		if err := w.Write(klineToCSVRow(k)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func klineFromCSVRow(row []string) (Kline, error) {
+	if len(row) < 7 {
+		return Kline{}, fmt.Errorf("invalid cache row: %v", row)
+	}
+
+	openTime, err := strconv.ParseInt(row[0], 10, 64)
+	if err != nil {
+		return Kline{}, err
+	}
+	open, _ := strconv.ParseFloat(row[1], 64)
+	high, _ := strconv.ParseFloat(row[2], 64)
+	low, _ := strconv.ParseFloat(row[3], 64)
+	close, _ := strconv.ParseFloat(row[4], 64)
+	volume, _ := strconv.ParseFloat(row[5], 64)
+	closeTime, err := strconv.ParseInt(row[6], 10, 64)
+	if err != nil {
+		return Kline{}, err
+	}
+
+	return Kline{
+		OpenTime:  openTime,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    volume,
+		CloseTime: closeTime,
+	}, nil
+}
+
+func klineToCSVRow(k Kline) []string {
+	return []string{
+		strconv.FormatInt(k.OpenTime, 10),
+		strconv.FormatFloat(k.Open, 'f', -1, 64),
+		strconv.FormatFloat(k.High, 'f', -1, 64),
+		strconv.FormatFloat(k.Low, 'f', -1, 64),
+		strconv.FormatFloat(k.Close, 'f', -1, 64),
+		strconv.FormatFloat(k.Volume, 'f', -1, 64),
+		strconv.FormatInt(k.CloseTime, 10),
+	}
+}
+
+// mergeKlines 合并两个K线切片，按OpenTime去重(重复时以b中的值为准)，结果按OpenTime升序排列
+func mergeKlines(a, b []Kline) []Kline {
+	byOpenTime := make(map[int64]Kline, len(a)+len(b))
+	for _, k := range a {
+		byOpenTime[k.OpenTime] = k
+	}
+	for _, k := range b {
+		byOpenTime[k.OpenTime] = k
+	}
+
+	merged := make([]Kline, 0, len(byOpenTime))
+	for _, k := range byOpenTime {
+		merged = append(merged, k)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].OpenTime < merged[j].OpenTime })
+	return merged
+}
+
+// tailKlines 返回最近limit根K线(limit<=0或不足limit时返回全部)
+func tailKlines(klines []Kline, limit int) []Kline {
+	if limit <= 0 || len(klines) <= limit {
+		return klines
+	}
+	return klines[len(klines)-limit:]
+}
+
+// closedOnly 假设接口返回的最后一根K线可能尚未收盘，缓存时需要把它剔除
+func closedOnly(klines []Kline) []Kline {
+	if len(klines) <= 1 {
+		return nil
+	}
+	return klines[:len(klines)-1]
+}
@@ -0,0 +1,48 @@
+package pattern
+
+import "github.com/PerryQiu/nofx/market/indicator"
+
+// detectDouble 识别需要看最后两根K线的形态: Bullish/Bearish Engulfing、
+// Piercing Line/Dark Cloud Cover
+func detectDouble(bars []indicator.Bar, atr14 float64, add addFunc) {
+	n := len(bars)
+	if n < 2 {
+		return
+	}
+	prev, cur := bars[n-2], bars[n-1]
+
+	prevBody := body(prev)
+	curBody := body(cur)
+	if prevBody < 0.05*atr14 {
+		// 前一根K线实体过小，吞没/刺透类形态的参照基准不可靠
+		return
+	}
+
+	// Bullish Engulfing: 前阴后阳，当前实体完全覆盖前一根实体
+	if !isBullish(prev) && isBullish(cur) && cur.Open <= prev.Close && cur.Close >= prev.Open {
+		strength := clampStrength(curBody / prevBody / 2)
+		add(BullishEngulfing, "Bullish Engulfing", true, strength)
+	}
+
+	// Bearish Engulfing: 前阳后阴，当前实体完全覆盖前一根实体
+	if isBullish(prev) && !isBullish(cur) && cur.Open >= prev.Close && cur.Close <= prev.Open {
+		strength := clampStrength(curBody / prevBody / 2)
+		add(BearishEngulfing, "Bearish Engulfing", false, strength)
+	}
+
+	prevMid := (prev.Open + prev.Close) / 2
+
+	// Piercing Line: 前阴后阳，当前开盘跳空低于前一根收盘，收盘收回到前一根实体中点以上(但不超过前开盘)
+	if !isBullish(prev) && isBullish(cur) &&
+		cur.Open < prev.Close && cur.Close > prevMid && cur.Close < prev.Open {
+		strength := clampStrength((cur.Close - prevMid) / (prev.Open - prevMid))
+		add(PiercingLine, "Piercing Line", true, strength)
+	}
+
+	// Dark Cloud Cover: 前阳后阴，当前开盘跳空高于前一根收盘，收盘跌破前一根实体中点(但不低于前开盘)
+	if isBullish(prev) && !isBullish(cur) &&
+		cur.Open > prev.Close && cur.Close < prevMid && cur.Close > prev.Open {
+		strength := clampStrength((prevMid - cur.Close) / (prevMid - prev.Open))
+		add(DarkCloudCover, "Dark Cloud Cover", false, strength)
+	}
+}
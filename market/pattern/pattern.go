@@ -0,0 +1,116 @@
+// Package pattern 基于K线的实体/影线相对ATR14的比例，识别常见的蜡烛图形态
+package pattern
+
+import "github.com/PerryQiu/nofx/market/indicator"
+
+// Shape位掩码常量，对应各类K线形态，可以按位组合记录同一时刻命中的多个形态
+const (
+	Doji uint64 = 1 << iota
+	Hammer
+	HangingMan
+	InvertedHammer
+	ShootingStar
+	BullishEngulfing
+	BearishEngulfing
+	PiercingLine
+	DarkCloudCover
+	MorningStar
+	EveningStar
+	ThreeWhiteSoldiers
+	ThreeBlackCrows
+	Marubozu
+)
+
+// Match 描述一次形态命中
+type Match struct {
+	Name     string
+	Bullish  bool
+	Strength float64 // 0~1，越大代表形态特征越典型
+}
+
+type addFunc func(bit uint64, name string, bullish bool, strength float64)
+
+// Classify 对bars末尾最多3根K线做形态识别。atr14用于把实体/影线的绝对价格差
+// 转换成跨币种通用的相对阈值，因此调用方应传入该symbol当前的14周期ATR。
+// 返回命中形态的位掩码，以及按识别顺序排列的可读命中列表
+func Classify(bars []indicator.Bar, atr14 float64) (shape uint64, matches []Match) {
+	if atr14 <= 0 || len(bars) == 0 {
+		return 0, nil
+	}
+
+	add := func(bit uint64, name string, bullish bool, strength float64) {
+		shape |= bit
+		matches = append(matches, Match{Name: name, Bullish: bullish, Strength: strength})
+	}
+
+	detectSingle(bars, atr14, add)
+	detectDouble(bars, atr14, add)
+	detectTriple(bars, atr14, add)
+
+	return shape, matches
+}
+
+func body(b indicator.Bar) float64 {
+	return absFloat(b.Close - b.Open)
+}
+
+func upperShadow(b indicator.Bar) float64 {
+	return b.High - maxFloat(b.Open, b.Close)
+}
+
+func lowerShadow(b indicator.Bar) float64 {
+	return minFloat(b.Open, b.Close) - b.Low
+}
+
+func candleRange(b indicator.Bar) float64 {
+	return b.High - b.Low
+}
+
+func isBullish(b indicator.Bar) bool {
+	return b.Close > b.Open
+}
+
+// precedingTrendBullish 粗略判断index之前(不含index)最多3根K线的涨跌方向，
+// 用来区分同一蜡烛形状在不同上下文里的含义(例如Hammer vs HangingMan)
+func precedingTrendBullish(bars []indicator.Bar, index int) bool {
+	start := index - 3
+	if start < 0 {
+		start = 0
+	}
+	if index <= start {
+		return false
+	}
+	return bars[index-1].Close >= bars[start].Close
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// clampStrength 把强度裁剪到[0, 1]区间
+func clampStrength(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,195 @@
+package pattern
+
+import (
+	"testing"
+
+	"github.com/PerryQiu/nofx/market/indicator"
+)
+
+func bar(open, high, low, close float64) indicator.Bar {
+	return indicator.Bar{Open: open, High: high, Low: low, Close: close, Volume: 100}
+}
+
+func hasBit(shape, bit uint64) bool {
+	return shape&bit != 0
+}
+
+func TestClassifyDoji(t *testing.T) {
+	bars := []indicator.Bar{bar(100, 101, 99, 100.02)}
+	shape, matches := Classify(bars, 10)
+	if !hasBit(shape, Doji) {
+		t.Fatalf("expected Doji, shape=%b matches=%v", shape, matches)
+	}
+}
+
+func TestClassifyHammer(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(110, 110, 105, 106),
+		bar(106, 106, 101, 102),
+		bar(102, 102, 97, 98),
+		bar(98, 98.55, 90, 98.5),
+	}
+	shape, _ := Classify(bars, 10)
+	if !hasBit(shape, Hammer) {
+		t.Fatalf("expected Hammer, shape=%b", shape)
+	}
+	if hasBit(shape, HangingMan) {
+		t.Fatalf("did not expect Hanging Man in a downtrend context, shape=%b", shape)
+	}
+}
+
+func TestClassifyHangingMan(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(85, 90, 85, 90),
+		bar(90, 95, 90, 95),
+		bar(95, 100, 95, 100),
+		bar(100, 100.55, 92, 100.5),
+	}
+	shape, _ := Classify(bars, 10)
+	if !hasBit(shape, HangingMan) {
+		t.Fatalf("expected Hanging Man, shape=%b", shape)
+	}
+}
+
+func TestClassifyShootingStar(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(85, 90, 85, 90),
+		bar(90, 95, 90, 95),
+		bar(95, 100, 95, 100),
+		bar(100, 108, 99.95, 100.5),
+	}
+	shape, _ := Classify(bars, 10)
+	if !hasBit(shape, ShootingStar) {
+		t.Fatalf("expected Shooting Star, shape=%b", shape)
+	}
+}
+
+func TestClassifyInvertedHammer(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(110, 110, 105, 106),
+		bar(106, 106, 101, 102),
+		bar(102, 102, 97, 98),
+		bar(98, 106, 97.95, 98.5),
+	}
+	shape, _ := Classify(bars, 10)
+	if !hasBit(shape, InvertedHammer) {
+		t.Fatalf("expected Inverted Hammer, shape=%b", shape)
+	}
+}
+
+func TestClassifyMarubozu(t *testing.T) {
+	bars := []indicator.Bar{bar(100, 110.01, 99.99, 110)}
+	shape, _ := Classify(bars, 5)
+	if !hasBit(shape, Marubozu) {
+		t.Fatalf("expected Marubozu, shape=%b", shape)
+	}
+}
+
+func TestClassifyBullishEngulfing(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(105, 106, 99, 100),
+		bar(99, 107, 98, 106),
+	}
+	shape, _ := Classify(bars, 5)
+	if !hasBit(shape, BullishEngulfing) {
+		t.Fatalf("expected Bullish Engulfing, shape=%b", shape)
+	}
+}
+
+func TestClassifyBearishEngulfing(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(100, 106, 99, 105),
+		bar(106, 107, 98, 99),
+	}
+	shape, _ := Classify(bars, 5)
+	if !hasBit(shape, BearishEngulfing) {
+		t.Fatalf("expected Bearish Engulfing, shape=%b", shape)
+	}
+}
+
+func TestClassifyPiercingLine(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(110, 111, 99, 100),
+		bar(98, 108, 97, 107),
+	}
+	shape, _ := Classify(bars, 5)
+	if !hasBit(shape, PiercingLine) {
+		t.Fatalf("expected Piercing Line, shape=%b", shape)
+	}
+}
+
+func TestClassifyDarkCloudCover(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(100, 111, 99, 110),
+		bar(112, 113, 102, 103),
+	}
+	shape, _ := Classify(bars, 5)
+	if !hasBit(shape, DarkCloudCover) {
+		t.Fatalf("expected Dark Cloud Cover, shape=%b", shape)
+	}
+}
+
+func TestClassifyMorningStar(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(110, 111, 99, 100),
+		bar(99, 100, 98, 99.5),
+		bar(100, 113, 99, 112),
+	}
+	shape, _ := Classify(bars, 10)
+	if !hasBit(shape, MorningStar) {
+		t.Fatalf("expected Morning Star, shape=%b", shape)
+	}
+}
+
+func TestClassifyEveningStar(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(100, 111, 99, 110),
+		bar(111, 112, 110, 111.5),
+		bar(110, 111, 97, 98),
+	}
+	shape, _ := Classify(bars, 10)
+	if !hasBit(shape, EveningStar) {
+		t.Fatalf("expected Evening Star, shape=%b", shape)
+	}
+}
+
+func TestClassifyThreeWhiteSoldiers(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(100, 105, 99, 104),
+		bar(101, 109, 100, 108),
+		bar(103, 113, 102, 112),
+	}
+	shape, _ := Classify(bars, 10)
+	if !hasBit(shape, ThreeWhiteSoldiers) {
+		t.Fatalf("expected Three White Soldiers, shape=%b", shape)
+	}
+}
+
+func TestClassifyThreeBlackCrows(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(112, 113, 107, 108),
+		bar(110, 111, 100, 101),
+		bar(103, 104, 97, 98),
+	}
+	shape, _ := Classify(bars, 10)
+	if !hasBit(shape, ThreeBlackCrows) {
+		t.Fatalf("expected Three Black Crows, shape=%b", shape)
+	}
+}
+
+func TestClassifyNoMatch(t *testing.T) {
+	bars := []indicator.Bar{
+		bar(100, 103, 98, 101.5),
+	}
+	shape, matches := Classify(bars, 10)
+	if shape != 0 || matches != nil {
+		t.Fatalf("expected no pattern to match a plain candle, shape=%b matches=%v", shape, matches)
+	}
+}
+
+func TestClassifyZeroATRReturnsNoMatch(t *testing.T) {
+	shape, matches := Classify([]indicator.Bar{bar(100, 101, 99, 100)}, 0)
+	if shape != 0 || matches != nil {
+		t.Fatalf("expected no match with zero ATR, shape=%b matches=%v", shape, matches)
+	}
+}
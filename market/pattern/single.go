@@ -0,0 +1,52 @@
+package pattern
+
+import "github.com/PerryQiu/nofx/market/indicator"
+
+// detectSingle 识别只需要看最后一根K线的形态: Doji、Hammer/HangingMan、
+// InvertedHammer/ShootingStar、Marubozu
+func detectSingle(bars []indicator.Bar, atr14 float64, add addFunc) {
+	n := len(bars)
+	last := bars[n-1]
+
+	b := body(last)
+	upper := upperShadow(last)
+	lower := lowerShadow(last)
+	rng := candleRange(last)
+
+	// Doji: 实体远小于ATR，开收盘价几乎相等
+	if dojiThreshold := 0.1 * atr14; b <= dojiThreshold {
+		strength := clampStrength(1 - b/dojiThreshold)
+		add(Doji, "Doji", isBullish(last), strength)
+	}
+
+	// Hammer/HangingMan: 实体偏小，下影线至少是实体的2倍，上影线很短
+	smallBody := b <= 0.3*atr14
+	longLowerShadow := lower >= 2*maxFloat(b, 0.05*atr14)
+	shortUpperShadow := upper <= 0.3*maxFloat(b, 0.05*atr14)
+	if smallBody && longLowerShadow && shortUpperShadow {
+		strength := clampStrength(lower / (2 * maxFloat(b, 0.05*atr14)))
+		if precedingTrendBullish(bars, n-1) {
+			add(HangingMan, "Hanging Man", false, strength)
+		} else {
+			add(Hammer, "Hammer", true, strength)
+		}
+	}
+
+	// InvertedHammer/ShootingStar: 实体偏小，上影线至少是实体的2倍，下影线很短
+	longUpperShadow := upper >= 2*maxFloat(b, 0.05*atr14)
+	shortLowerShadow := lower <= 0.3*maxFloat(b, 0.05*atr14)
+	if smallBody && longUpperShadow && shortLowerShadow {
+		strength := clampStrength(upper / (2 * maxFloat(b, 0.05*atr14)))
+		if precedingTrendBullish(bars, n-1) {
+			add(ShootingStar, "Shooting Star", false, strength)
+		} else {
+			add(InvertedHammer, "Inverted Hammer", true, strength)
+		}
+	}
+
+	// Marubozu: 实体几乎占满整根K线的波幅，上下影线都很短
+	if rng > 0 && b >= 0.9*rng && rng >= 0.5*atr14 {
+		strength := clampStrength(b / rng)
+		add(Marubozu, "Marubozu", isBullish(last), strength)
+	}
+}
@@ -0,0 +1,56 @@
+package pattern
+
+import "github.com/PerryQiu/nofx/market/indicator"
+
+// detectTriple 识别需要看最后三根K线的形态: Morning/Evening Star、
+// Three White Soldiers/Three Black Crows
+func detectTriple(bars []indicator.Bar, atr14 float64, add addFunc) {
+	n := len(bars)
+	if n < 3 {
+		return
+	}
+	first, second, third := bars[n-3], bars[n-2], bars[n-1]
+
+	firstBody := body(first)
+	secondBody := body(second)
+	thirdBody := body(third)
+	firstMid := (first.Open + first.Close) / 2
+
+	// Morning Star: 大阴线 -> 跳空小实体(十字星/陀螺) -> 大阳线收复到第一根实体中点以上
+	if !isBullish(first) && firstBody >= 0.5*atr14 &&
+		secondBody <= 0.3*atr14 && maxFloat(second.Open, second.Close) < first.Close &&
+		isBullish(third) && thirdBody >= 0.5*atr14 &&
+		third.Close > firstMid {
+		strength := clampStrength((third.Close - firstMid) / (first.Open - firstMid))
+		add(MorningStar, "Morning Star", true, strength)
+	}
+
+	// Evening Star: 大阳线 -> 跳空小实体 -> 大阴线收低到第一根实体中点以下
+	if isBullish(first) && firstBody >= 0.5*atr14 &&
+		secondBody <= 0.3*atr14 && minFloat(second.Open, second.Close) > first.Close &&
+		!isBullish(third) && thirdBody >= 0.5*atr14 &&
+		third.Close < firstMid {
+		strength := clampStrength((firstMid - third.Close) / (firstMid - first.Open))
+		add(EveningStar, "Evening Star", false, strength)
+	}
+
+	// Three White Soldiers: 连续三根阳线，每根都在前一根实体内开盘且收盘价逐根抬高
+	if isBullish(first) && isBullish(second) && isBullish(third) &&
+		firstBody >= 0.3*atr14 && secondBody >= 0.3*atr14 && thirdBody >= 0.3*atr14 &&
+		second.Open >= first.Open && second.Open <= first.Close &&
+		third.Open >= second.Open && third.Open <= second.Close &&
+		second.Close > first.Close && third.Close > second.Close {
+		strength := clampStrength((thirdBody + secondBody + firstBody) / (3 * atr14))
+		add(ThreeWhiteSoldiers, "Three White Soldiers", true, strength)
+	}
+
+	// Three Black Crows: 连续三根阴线，每根都在前一根实体内开盘且收盘价逐根走低
+	if !isBullish(first) && !isBullish(second) && !isBullish(third) &&
+		firstBody >= 0.3*atr14 && secondBody >= 0.3*atr14 && thirdBody >= 0.3*atr14 &&
+		second.Open <= first.Open && second.Open >= first.Close &&
+		third.Open <= second.Open && third.Open >= second.Close &&
+		second.Close < first.Close && third.Close < second.Close {
+		strength := clampStrength((thirdBody + secondBody + firstBody) / (3 * atr14))
+		add(ThreeBlackCrows, "Three Black Crows", false, strength)
+	}
+}
@@ -0,0 +1,257 @@
+package market
+
+// Series 是一个只增长的时间序列，为增量计算出来的指标值提供只读的回看能力
+type Series struct {
+	values []float64
+}
+
+// NewSeries 创建一个空的Series
+func NewSeries() *Series {
+	return &Series{}
+}
+
+func (s *Series) push(v float64) {
+	s.values = append(s.values, v)
+}
+
+// Length 返回序列当前已有多少个值
+func (s *Series) Length() int {
+	return len(s.values)
+}
+
+// Last 返回倒数第i个值(i=0即最新值)，i超出已有长度时返回0
+func (s *Series) Last(i int) float64 {
+	idx := len(s.values) - 1 - i
+	if idx < 0 || idx >= len(s.values) {
+		return 0
+	}
+	return s.values[idx]
+}
+
+// Recent 返回最近n个值，按时间升序(旧→新)排列，不足n个时返回现有的全部
+func (s *Series) Recent(n int) []float64 {
+	if n > len(s.values) {
+		n = len(s.values)
+	}
+	start := len(s.values) - n
+	out := make([]float64, n)
+	copy(out, s.values[start:])
+	return out
+}
+
+// EMAState 维护EMA的增量计算状态，每根新K线只需O(1)更新，
+// 避免calculateEMA那样每次都重新扫描整段K线
+type EMAState struct {
+	period      int
+	multiplier  float64
+	value       float64
+	seeded      bool
+	seedSum     float64
+	seedCount   int
+	seriesValue *Series
+}
+
+// NewEMAState 创建一个period周期的EMA增量状态
+func NewEMAState(period int) *EMAState {
+	return &EMAState{
+		period:      period,
+		multiplier:  2.0 / float64(period+1),
+		seriesValue: NewSeries(),
+	}
+}
+
+// Update 输入最新收盘价，返回更新后的EMA值(未凑够period根K线前返回0)
+func (e *EMAState) Update(close float64) float64 {
+	if !e.seeded {
+		e.seedSum += close
+		e.seedCount++
+		if e.seedCount < e.period {
+			return 0
+		}
+		e.value = e.seedSum / float64(e.period)
+		e.seeded = true
+		e.seriesValue.push(e.value)
+		return e.value
+	}
+
+	e.value = (close-e.value)*e.multiplier + e.value
+	e.seriesValue.push(e.value)
+	return e.value
+}
+
+// Series 返回该EMA逐根K线的历史值
+func (e *EMAState) Series() *Series {
+	return e.seriesValue
+}
+
+// MACDState 维护MACD(快EMA-慢EMA)的增量计算状态
+type MACDState struct {
+	fast        *EMAState
+	slow        *EMAState
+	seriesValue *Series
+}
+
+// NewMACDState 创建一个MACDState，fastPeriod/slowPeriod通常为12/26
+func NewMACDState(fastPeriod, slowPeriod int) *MACDState {
+	return &MACDState{
+		fast:        NewEMAState(fastPeriod),
+		slow:        NewEMAState(slowPeriod),
+		seriesValue: NewSeries(),
+	}
+}
+
+// Update 输入最新收盘价，返回更新后的MACD值(两条EMA未就绪前返回0)
+func (m *MACDState) Update(close float64) float64 {
+	fast := m.fast.Update(close)
+	slow := m.slow.Update(close)
+	if m.fast.seriesValue.Length() == 0 || m.slow.seriesValue.Length() == 0 {
+		return 0
+	}
+	macd := fast - slow
+	m.seriesValue.push(macd)
+	return macd
+}
+
+// Series 返回MACD逐根K线的历史值
+func (m *MACDState) Series() *Series {
+	return m.seriesValue
+}
+
+// RSIState 维护RSI的增量计算状态(Wilder平滑)
+type RSIState struct {
+	period      int
+	prevClose   float64
+	hasPrev     bool
+	avgGain     float64
+	avgLoss     float64
+	seedGain    float64
+	seedLoss    float64
+	seedCount   int
+	seeded      bool
+	seriesValue *Series
+}
+
+// NewRSIState 创建一个period周期的RSI增量状态
+func NewRSIState(period int) *RSIState {
+	return &RSIState{period: period, seriesValue: NewSeries()}
+}
+
+// Update 输入最新收盘价，返回更新后的RSI值(未凑够period根变化前返回0)
+func (r *RSIState) Update(close float64) float64 {
+	if !r.hasPrev {
+		r.prevClose = close
+		r.hasPrev = true
+		return 0
+	}
+
+	change := close - r.prevClose
+	r.prevClose = close
+
+	gain, loss := 0.0, 0.0
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	if !r.seeded {
+		r.seedGain += gain
+		r.seedLoss += loss
+		r.seedCount++
+		if r.seedCount < r.period {
+			return 0
+		}
+		r.avgGain = r.seedGain / float64(r.period)
+		r.avgLoss = r.seedLoss / float64(r.period)
+		r.seeded = true
+	} else {
+		r.avgGain = (r.avgGain*float64(r.period-1) + gain) / float64(r.period)
+		r.avgLoss = (r.avgLoss*float64(r.period-1) + loss) / float64(r.period)
+	}
+
+	var rsi float64
+	if r.avgLoss == 0 {
+		rsi = 100
+	} else {
+		rs := r.avgGain / r.avgLoss
+		rsi = 100 - (100 / (1 + rs))
+	}
+
+	r.seriesValue.push(rsi)
+	return rsi
+}
+
+// Series 返回RSI逐根K线的历史值
+func (r *RSIState) Series() *Series {
+	return r.seriesValue
+}
+
+// ATRState 维护ATR的增量计算状态(Wilder平滑)
+type ATRState struct {
+	period      int
+	prevClose   float64
+	hasPrev     bool
+	value       float64
+	seedSum     float64
+	seedCount   int
+	seeded      bool
+	seriesValue *Series
+}
+
+// NewATRState 创建一个period周期的ATR增量状态
+func NewATRState(period int) *ATRState {
+	return &ATRState{period: period, seriesValue: NewSeries()}
+}
+
+// Update 输入最新K线的high/low/close，返回更新后的ATR值(未凑够period根TR前返回0)
+func (a *ATRState) Update(high, low, close float64) float64 {
+	if !a.hasPrev {
+		a.prevClose = close
+		a.hasPrev = true
+		return 0
+	}
+
+	tr := trueRange(high, low, a.prevClose)
+	a.prevClose = close
+
+	if !a.seeded {
+		a.seedSum += tr
+		a.seedCount++
+		if a.seedCount < a.period {
+			return 0
+		}
+		a.value = a.seedSum / float64(a.period)
+		a.seeded = true
+	} else {
+		a.value = (a.value*float64(a.period-1) + tr) / float64(a.period)
+	}
+
+	a.seriesValue.push(a.value)
+	return a.value
+}
+
+// Series 返回ATR逐根K线的历史值
+func (a *ATRState) Series() *Series {
+	return a.seriesValue
+}
+
+func trueRange(high, low, prevClose float64) float64 {
+	tr1 := high - low
+	tr2 := absFloat(high - prevClose)
+	tr3 := absFloat(low - prevClose)
+	return maxFloat(tr1, maxFloat(tr2, tr3))
+}
+
+func absFloat(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
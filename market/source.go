@@ -0,0 +1,39 @@
+package market
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Source 是行情数据源的抽象接口。Binance只是其中一种实现，
+// 其他交易所(Bybit、OKX等)或本地历史数据都可以实现该接口，
+// 从而被 GetWithSource/CompositeSource 复用同一套指标计算管线。
+type Source interface {
+	// FetchKlines 获取指定symbol、interval的K线数据，最多limit根，按时间升序返回
+	FetchKlines(symbol, interval string, limit int) ([]Kline, error)
+	// FetchOpenInterest 获取合约持仓量数据
+	FetchOpenInterest(symbol string) (*OIData, error)
+	// FetchFundingRate 获取资金费率
+	FetchFundingRate(symbol string) (float64, error)
+	// Normalize 将用户输入的symbol标准化为该数据源使用的交易对格式
+	Normalize(symbol string) string
+}
+
+// DefaultSource 是未显式指定数据源时使用的默认实现，保持向后兼容
+var DefaultSource Source = NewBinanceSource()
+
+// parseFloat 解析float值(交易所接口里数字既可能是字符串也可能是number)
+func parseFloat(v interface{}) (float64, error) {
+	switch val := v.(type) {
+	case string:
+		return strconv.ParseFloat(val, 64)
+	case float64:
+		return val, nil
+	case int:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	default:
+		return 0, fmt.Errorf("unsupported type: %T", v)
+	}
+}
@@ -0,0 +1,124 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// klineStreamEvent 是Binance `@kline_<interval>` 推送消息中我们关心的部分
+type klineStreamEvent struct {
+	Kline struct {
+		OpenTime  int64  `json:"t"`
+		CloseTime int64  `json:"T"`
+		High      string `json:"h"`
+		Low       string `json:"l"`
+		Close     string `json:"c"`
+		Closed    bool   `json:"x"`
+	} `json:"k"`
+}
+
+// streamState 维护一路Stream连接上的增量指标状态，K线收盘时才真正推进状态，
+// 避免同一根尚未收盘的K线被反复计入Wilder平滑
+type streamState struct {
+	symbol   string
+	interval string
+	mid      *Series
+	ema20    *EMAState
+	macd     *MACDState
+	rsi7     *RSIState
+	atr14    *ATRState
+}
+
+func newStreamState(symbol, interval string) *streamState {
+	return &streamState{
+		symbol:   symbol,
+		interval: interval,
+		mid:      NewSeries(),
+		ema20:    NewEMAState(20),
+		macd:     NewMACDState(12, 26),
+		rsi7:     NewRSIState(7),
+		atr14:    NewATRState(14),
+	}
+}
+
+// advance 用一根已收盘的K线推进所有增量指标状态，返回最新快照
+func (s *streamState) advance(high, low, close float64) *Data {
+	s.mid.push(close)
+	s.ema20.Update(close)
+	s.macd.Update(close)
+	s.rsi7.Update(close)
+	s.atr14.Update(high, low, close)
+	return s.snapshot(close)
+}
+
+// preview 不修改增量状态，只用最新(尚未收盘)的价格和已有指标值拼一份快照，供IncludePartial使用
+func (s *streamState) preview(close float64) *Data {
+	return s.snapshot(close)
+}
+
+func (s *streamState) snapshot(price float64) *Data {
+	return &Data{
+		Symbol:       s.symbol,
+		CurrentPrice: price,
+		CurrentEMA20: s.ema20.Series().Last(0),
+		CurrentMACD:  s.macd.Series().Last(0),
+		CurrentRSI7:  s.rsi7.Series().Last(0),
+		OpenInterest: &OIData{},
+		IntradaySeries: &IntradayData{
+			MidPrices:   s.mid.Recent(10),
+			EMA20Values: s.ema20.Series().Recent(10),
+			MACDValues:  s.macd.Series().Recent(10),
+			RSI7Values:  s.rsi7.Series().Recent(10),
+			Interval:    s.interval,
+		},
+	}
+}
+
+// Stream 订阅Binance永续合约的`@kline_<interval>`推送，每当一根K线收盘就在返回的channel上
+// 推一份*Data快照；includePartial为true时，未收盘K线的每次tick也会推一份(仅CurrentPrice更新，
+// 指标沿用上一根收盘值)。调用方应在用完后调用返回的stop函数关闭连接。
+func Stream(symbol, interval string, includePartial bool) (<-chan *Data, func() error, error) {
+	symbol = Normalize(symbol)
+	streamName := fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), interval)
+	wsURL := fmt.Sprintf("wss://fstream.binance.com/ws/%s", streamName)
+
+	conn, err := dialWebSocket(wsURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("连接%s K线推送失败: %v", symbol, err)
+	}
+
+	out := make(chan *Data, 16)
+	state := newStreamState(symbol, interval)
+
+	go func() {
+		defer close(out)
+		for {
+			msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var event klineStreamEvent
+			if err := json.Unmarshal(msg, &event); err != nil {
+				continue
+			}
+
+			if !event.Kline.Closed {
+				if includePartial {
+					close_, _ := strconv.ParseFloat(event.Kline.Close, 64)
+					out <- state.preview(close_)
+				}
+				continue
+			}
+
+			high, _ := strconv.ParseFloat(event.Kline.High, 64)
+			low, _ := strconv.ParseFloat(event.Kline.Low, 64)
+			close_, _ := strconv.ParseFloat(event.Kline.Close, 64)
+			out <- state.advance(high, low, close_)
+		}
+	}()
+
+	return out, conn.Close, nil
+}
@@ -0,0 +1,53 @@
+package market
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket 是一个简单的令牌桶限流器，用来控制对交易所接口的请求权重。
+// take会在桶内权重不足时阻塞等待，而不是直接报错，这样调用方不需要自己写重试循环
+type tokenBucket struct {
+	mu           sync.Mutex
+	capacity     float64
+	tokens       float64
+	refillPerSec float64
+	lastRefill   time.Time
+}
+
+// newTokenBucket 创建一个容量为capacity、每秒回充refillPerSec权重的令牌桶
+func newTokenBucket(capacity, refillPerSec float64) *tokenBucket {
+	return &tokenBucket{
+		capacity:     capacity,
+		tokens:       capacity,
+		refillPerSec: refillPerSec,
+		lastRefill:   time.Now(),
+	}
+}
+
+// take 阻塞直到桶内有至少weight权重可用，然后扣除
+func (b *tokenBucket) take(weight float64) {
+	for {
+		b.mu.Lock()
+		b.refill()
+		if b.tokens >= weight {
+			b.tokens -= weight
+			b.mu.Unlock()
+			return
+		}
+		deficit := weight - b.tokens
+		wait := time.Duration(deficit / b.refillPerSec * float64(time.Second))
+		b.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens += elapsed * b.refillPerSec
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.lastRefill = now
+}
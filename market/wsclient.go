@@ -0,0 +1,201 @@
+package market
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// wsConn 是一个极简的RFC6455客户端，只实现Stream()所需的子集：
+// 单帧文本消息的接收、ping/pong应答。Binance的kline推送消息足够小，
+// 不会跨帧传输，因此这里不处理消息分片。
+type wsConn struct {
+	conn io.ReadWriteCloser
+}
+
+// dialWebSocket 建立到wss地址的TLS连接并完成WebSocket握手
+func dialWebSocket(rawurl string) (*wsConn, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	if !strings.Contains(host, ":") {
+		host += ":443"
+	}
+
+	conn, err := tls.Dial("tcp", host, &tls.Config{ServerName: u.Hostname()})
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	path := u.RequestURI()
+	req := fmt.Sprintf("GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n",
+		path, u.Hostname(), key)
+
+	if _, err := conn.Write([]byte(req)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := readHandshakeResponse(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn}, nil
+}
+
+// readHandshakeResponse 读取并校验服务端的101响应头，读到空行为止
+func readHandshakeResponse(r io.Reader) error {
+	var buf [1]byte
+	var line bytes.Buffer
+	statusRead := false
+
+	for {
+		if _, err := r.Read(buf[:]); err != nil {
+			return err
+		}
+		line.WriteByte(buf[0])
+
+		if buf[0] != '\n' {
+			continue
+		}
+
+		text := strings.TrimRight(line.String(), "\r\n")
+		line.Reset()
+
+		if !statusRead {
+			if !strings.Contains(text, "101") {
+				return fmt.Errorf("websocket握手失败: %s", text)
+			}
+			statusRead = true
+			continue
+		}
+
+		if text == "" {
+			return nil
+		}
+	}
+}
+
+// ReadMessage 读取一条完整的文本消息，自动应答ping帧
+func (w *wsConn) ReadMessage() ([]byte, error) {
+	for {
+		opcode, payload, err := w.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case 0x1: // text frame
+			return payload, nil
+		case 0x9: // ping -> 回复pong
+			if err := w.writeFrame(0xA, payload); err != nil {
+				return nil, err
+			}
+		case 0x8: // close
+			return nil, fmt.Errorf("websocket连接已被对端关闭")
+		}
+	}
+}
+
+func (w *wsConn) readFrame() (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(w.conn, header); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(w.conn, ext); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(w.conn, ext); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext))
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(w.conn, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(w.conn, payload); err != nil {
+		return 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
+
+// writeFrame 按客户端要求对payload做掩码后写出一帧(目前只用于回复pong)
+func (w *wsConn) writeFrame(opcode byte, payload []byte) error {
+	var buf bytes.Buffer
+	buf.WriteByte(0x80 | opcode) // FIN=1
+
+	maskKey := make([]byte, 4)
+	if _, err := rand.Read(maskKey); err != nil {
+		return err
+	}
+
+	length := len(payload)
+	switch {
+	case length < 126:
+		buf.WriteByte(0x80 | byte(length))
+	case length <= 0xFFFF:
+		buf.WriteByte(0x80 | 126)
+		ext := make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(length))
+		buf.Write(ext)
+	default:
+		buf.WriteByte(0x80 | 127)
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(length))
+		buf.Write(ext)
+	}
+
+	buf.Write(maskKey)
+	masked := make([]byte, length)
+	for i, b := range payload {
+		masked[i] = b ^ maskKey[i%4]
+	}
+	buf.Write(masked)
+
+	_, err := w.conn.Write(buf.Bytes())
+	return err
+}
+
+func (w *wsConn) Close() error {
+	return w.conn.Close()
+}